@@ -0,0 +1,201 @@
+package common
+
+import (
+	"iter"
+)
+
+// DeduplicateBy keeps the first value for every key.
+func DeduplicateBy[T any, K comparable](values []T, key func(T) K) []T {
+	seen := make(map[K]struct{})
+	result := make([]T, 0, len(values))
+
+	for _, v := range values {
+		k := key(v)
+		if _, exists := seen[k]; !exists {
+			seen[k] = struct{}{}
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+// GroupBy keeps the order of values within each group.
+func GroupBy[T any, K comparable](values []T, key func(T) K) map[K][]T {
+	result := make(map[K][]T)
+	for _, v := range values {
+		k := key(v)
+		result[k] = append(result[k], v)
+	}
+	return result
+}
+
+// Partition splits values into those matching predicate and the rest.
+func Partition[T any](values []T, predicate func(T) bool) ([]T, []T) {
+	matched := make([]T, 0, len(values))
+	rest := make([]T, 0)
+	for _, v := range values {
+		if predicate(v) {
+			matched = append(matched, v)
+		} else {
+			rest = append(rest, v)
+		}
+	}
+	return matched, rest
+}
+
+// Chunk splits values into slices of size, the last one may be shorter. The
+// chunks share memory with values. It panics if size is less than 1.
+func Chunk[T any](values []T, size int) [][]T {
+	if size < 1 {
+		panic("common: chunk size must be at least 1")
+	}
+	result := make([][]T, 0, (len(values)+size-1)/size)
+	for start := 0; start < len(values); start += size {
+		end := min(start+size, len(values))
+		result = append(result, values[start:end:end])
+	}
+	return result
+}
+
+func Map[T, R any](values []T, fn func(T) R) []R {
+	result := make([]R, len(values))
+	for i, v := range values {
+		result[i] = fn(v)
+	}
+	return result
+}
+
+func Filter[T any](values []T, predicate func(T) bool) []T {
+	result := make([]T, 0, len(values))
+	for _, v := range values {
+		if predicate(v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func Reduce[T, A any](values []T, initial A, fn func(A, T) A) A {
+	result := initial
+	for _, v := range values {
+		result = fn(result, v)
+	}
+	return result
+}
+
+// Associate builds a map from values, later values win on duplicate keys.
+func Associate[T any, K comparable, V any](values []T, fn func(T) (K, V)) map[K]V {
+	result := make(map[K]V, len(values))
+	for _, v := range values {
+		k, value := fn(v)
+		result[k] = value
+	}
+	return result
+}
+
+// Union returns the distinct values of a and then b in order of appearance.
+func Union[T comparable](a, b []T) []T {
+	return Deduplicate(append(append(make([]T, 0, len(a)+len(b)), a...), b...))
+}
+
+// Intersect returns the distinct values of a that are also in b.
+func Intersect[T comparable](a, b []T) []T {
+	in := toSet(b)
+	return Deduplicate(Filter(a, func(v T) bool {
+		_, ok := in[v]
+		return ok
+	}))
+}
+
+// Difference returns the distinct values of a that are not in b.
+func Difference[T comparable](a, b []T) []T {
+	in := toSet(b)
+	return Deduplicate(Filter(a, func(v T) bool {
+		_, ok := in[v]
+		return !ok
+	}))
+}
+
+func toSet[T comparable](values []T) map[T]struct{} {
+	result := make(map[T]struct{}, len(values))
+	for _, v := range values {
+		result[v] = struct{}{}
+	}
+	return result
+}
+
+// The Seq variants are lazy: they pull from seq only as far as the consumer
+// iterates, so they work on large or endless inputs.
+
+func MapSeq[T, R any](seq iter.Seq[T], fn func(T) R) iter.Seq[R] {
+	return func(yield func(R) bool) {
+		for v := range seq {
+			if !yield(fn(v)) {
+				return
+			}
+		}
+	}
+}
+
+func FilterSeq[T any](seq iter.Seq[T], predicate func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range seq {
+			if predicate(v) && !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// DeduplicateSeq remembers every value seen so far.
+func DeduplicateSeq[T comparable](seq iter.Seq[T]) iter.Seq[T] {
+	return DeduplicateBySeq(seq, func(v T) T { return v })
+}
+
+func DeduplicateBySeq[T any, K comparable](seq iter.Seq[T], key func(T) K) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		seen := make(map[K]struct{})
+		for v := range seq {
+			k := key(v)
+			if _, exists := seen[k]; exists {
+				continue
+			}
+			seen[k] = struct{}{}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// ChunkSeq yields new slices of size values, the last one may be shorter. It
+// panics if size is less than 1.
+func ChunkSeq[T any](seq iter.Seq[T], size int) iter.Seq[[]T] {
+	if size < 1 {
+		panic("common: chunk size must be at least 1")
+	}
+	return func(yield func([]T) bool) {
+		chunk := make([]T, 0, size)
+		for v := range seq {
+			chunk = append(chunk, v)
+			if len(chunk) == size {
+				if !yield(chunk) {
+					return
+				}
+				chunk = make([]T, 0, size)
+			}
+		}
+		if len(chunk) > 0 {
+			yield(chunk)
+		}
+	}
+}
+
+func ReduceSeq[T, A any](seq iter.Seq[T], initial A, fn func(A, T) A) A {
+	result := initial
+	for v := range seq {
+		result = fn(result, v)
+	}
+	return result
+}
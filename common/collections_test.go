@@ -0,0 +1,102 @@
+package common
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeduplicateBy(t *testing.T) {
+	result := DeduplicateBy([]string{"Apple", "apple", "Pear", "APPLE", "pear"}, strings.ToLower)
+	assert.Equal(t, []string{"Apple", "Pear"}, result)
+	assert.Equal(t, []int{}, DeduplicateBy([]int(nil), func(v int) int { return v }))
+}
+
+func TestGroupBy(t *testing.T) {
+	result := GroupBy([]int{1, 2, 3, 4, 5}, func(v int) bool { return v%2 == 0 })
+	assert.Equal(t, map[bool][]int{true: {2, 4}, false: {1, 3, 5}}, result)
+}
+
+func TestPartition(t *testing.T) {
+	even, odd := Partition([]int{1, 2, 3, 4, 5}, func(v int) bool { return v%2 == 0 })
+	assert.Equal(t, []int{2, 4}, even)
+	assert.Equal(t, []int{1, 3, 5}, odd)
+}
+
+func TestChunk(t *testing.T) {
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, Chunk([]int{1, 2, 3, 4, 5}, 2))
+	assert.Empty(t, Chunk([]int{}, 3))
+	assert.Panics(t, func() { Chunk([]int{1}, 0) })
+
+	// Appending to a chunk must not overwrite the next one.
+	chunks := Chunk([]int{1, 2, 3, 4}, 2)
+	_ = append(chunks[0], 9)
+	assert.Equal(t, []int{3, 4}, chunks[1])
+}
+
+func TestMapFilterReduce(t *testing.T) {
+	values := []int{1, 2, 3, 4}
+	assert.Equal(t, []string{"1", "2", "3", "4"}, Map(values, func(v int) string { return string(rune('0' + v)) }))
+	assert.Equal(t, []int{3, 4}, Filter(values, func(v int) bool { return v > 2 }))
+	assert.Equal(t, 10, Reduce(values, 0, func(sum, v int) int { return sum + v }))
+}
+
+func TestAssociate(t *testing.T) {
+	result := Associate([]string{"a", "bb", "cc"}, func(v string) (int, string) { return len(v), v })
+	assert.Equal(t, map[int]string{1: "a", 2: "cc"}, result)
+}
+
+func TestSetOperations(t *testing.T) {
+	a := []int{1, 2, 2, 3}
+	b := []int{3, 4, 2}
+	assert.Equal(t, []int{1, 2, 3, 4}, Union(a, b))
+	assert.Equal(t, []int{2, 3}, Intersect(a, b))
+	assert.Equal(t, []int{1}, Difference(a, b))
+	assert.Empty(t, Intersect(a, nil))
+}
+
+func TestSeqVariants(t *testing.T) {
+	// naturals never ends, the helpers must stop pulling when the consumer does.
+	naturals := func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+
+	evens := FilterSeq(naturals, func(v int) bool { return v%2 == 0 })
+	squares := MapSeq(evens, func(v int) int { return v * v })
+	var result []int
+	for v := range squares {
+		if len(result) == 4 {
+			break
+		}
+		result = append(result, v)
+	}
+	assert.Equal(t, []int{0, 4, 16, 36}, result)
+
+	mod3 := MapSeq(naturals, func(v int) int { return v % 3 })
+	var distinct []int
+	for v := range DeduplicateSeq(mod3) {
+		distinct = append(distinct, v)
+		if len(distinct) == 3 {
+			break
+		}
+	}
+	assert.Equal(t, []int{0, 1, 2}, distinct)
+
+	words := slices.Values([]string{"Go", "go", "Rust", "GO"})
+	assert.Equal(t, []string{"Go", "Rust"}, slices.Collect(DeduplicateBySeq(words, strings.ToLower)))
+
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, slices.Collect(ChunkSeq(slices.Values([]int{1, 2, 3, 4, 5}), 2)))
+	for chunk := range ChunkSeq(naturals, 3) {
+		assert.Equal(t, []int{0, 1, 2}, chunk)
+		break
+	}
+	assert.Panics(t, func() { ChunkSeq(naturals, 0) })
+
+	assert.Equal(t, 15, ReduceSeq(slices.Values([]int{1, 2, 3, 4, 5}), 0, func(sum, v int) int { return sum + v }))
+}
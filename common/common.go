@@ -34,9 +34,9 @@ func FilterBlank(values []string) []string {
 	return result
 }
 
-func Deduplicate(values []string) []string {
-	seen := make(map[string]struct{})
-	result := make([]string, 0, len(values))
+func Deduplicate[T comparable](values []T) []T {
+	seen := make(map[T]struct{})
+	result := make([]T, 0, len(values))
 
 	for _, v := range values {
 		if _, exists := seen[v]; !exists {
@@ -48,8 +48,15 @@ func Deduplicate(values []string) []string {
 	return result
 }
 
+// Deprecated: EmailRegExp rejects valid addresses, use ParseEmail.
 var EmailRegExp = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
 
+// IsValidEmail reports whether email is a bare address (no display name or
+// surrounding whitespace) accepted by ParseEmail.
 func IsValidEmail(email string) bool {
-	return EmailRegExp.MatchString(email)
+	if strings.TrimSpace(email) != email {
+		return false
+	}
+	address, err := ParseEmail(email)
+	return err == nil && address.DisplayName == "" && !strings.ContainsAny(email, "<>")
 }
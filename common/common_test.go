@@ -127,6 +127,9 @@ func TestIsValidEmail(t *testing.T) {
 			{value: "anything", expected: false},
 			{value: "anything@domain", expected: false},
 			{value: "anything@domain.sk", expected: true},
+			{value: " a@b.com ", expected: false},
+			{value: "a@b.com\n", expected: false},
+			{value: "a@b.com\r\n", expected: false},
 		}
 
 		for _, test := range tests {
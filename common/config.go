@@ -1,18 +1,52 @@
 package common
 
 import (
+	"errors"
 	"fmt"
 	"log"
-	"os"
+	"math/big"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 )
 
 func EnvSafe(key string) (string, error) {
-	if env, ok := os.LookupEnv(key); ok && !IsBlank(env) {
+	return envSafeFrom(CurrentConfigSource(), key)
+}
+
+var ErrConfigNotSet = errors.New("not set")
+
+func envSafeFrom(source ConfigSource, key string) (string, error) {
+	if env, from, ok := resolveConfig(source, key); ok {
+		recordConfig(key, env, from, false, false)
 		return env, nil
 	}
-	return "", fmt.Errorf("configuration property %s not set", key)
+	if path, _, ok := resolveConfig(source, key+secretFileSuffix); ok {
+		path = strings.TrimSpace(path)
+		value, err := readSecretFile(key, path)
+		if err != nil {
+			return "", err
+		}
+		recordConfig(key, value, path, false, true)
+		return value, nil
+	}
+	recordConfig(key, "", "", false, false)
+	return "", fmt.Errorf("configuration property %s %w", key, ErrConfigNotSet)
+}
+
+func resolveConfig(source ConfigSource, key string) (string, string, bool) {
+	if layered, ok := source.(*LayeredSource); ok {
+		value, from, ok := layered.Resolve(key)
+		if !ok {
+			return "", "", false
+		}
+		return value, from.Name(), true
+	}
+	if value, ok := source.Lookup(key); ok && !IsBlank(value) {
+		return value, source.Name(), true
+	}
+	return "", "", false
 }
 
 func EnvIntSafe(key string) (int, error) {
@@ -39,6 +73,100 @@ func EnvBoolSafe(key string) (bool, error) {
 	return result, nil
 }
 
+func EnvInt64Safe(key string) (int64, error) {
+	s, err := EnvSafe(key)
+	if err != nil {
+		return 0, err
+	}
+	result, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("configuration property %s wrong format: %v", key, err)
+	}
+	return result, nil
+}
+
+func EnvFloatSafe(key string) (float64, error) {
+	s, err := EnvSafe(key)
+	if err != nil {
+		return 0, err
+	}
+	result, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, fmt.Errorf("configuration property %s wrong format: %v", key, err)
+	}
+	return result, nil
+}
+
+func EnvDurationSafe(key string) (time.Duration, error) {
+	s, err := EnvSafe(key)
+	if err != nil {
+		return 0, err
+	}
+	result, err := time.ParseDuration(strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("configuration property %s wrong format: %v", key, err)
+	}
+	return result, nil
+}
+
+func EnvURLSafe(key string) (*url.URL, error) {
+	s, err := EnvSafe(key)
+	if err != nil {
+		return nil, err
+	}
+	result, err := url.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("configuration property %s wrong format: %v", key, err)
+	}
+	if result.Scheme == "" {
+		return nil, fmt.Errorf("configuration property %s wrong format: missing URL scheme in %q", key, s)
+	}
+	return result, nil
+}
+
+func EnvRatSafe(key string, scale int, mode RoundingMode) (*big.Rat, error) {
+	s, err := EnvSafe(key)
+	if err != nil {
+		return nil, err
+	}
+	if scale < 0 {
+		return nil, fmt.Errorf("configuration property %s: scale must be >= 0, got %d", key, scale)
+	}
+	d, err := ParseDecimal(s)
+	if err != nil {
+		return nil, fmt.Errorf("configuration property %s wrong format: %v", key, err)
+	}
+	result, err := RescaleSafe(d.Rat(), scale, mode)
+	if err != nil {
+		return nil, fmt.Errorf("configuration property %s: %v", key, err)
+	}
+	return result, nil
+}
+
+func EnvTimeSafe(key string) (time.Time, error) {
+	s, err := EnvSafe(key)
+	if err != nil {
+		return time.Time{}, err
+	}
+	result, err := time.Parse(time.RFC3339, strings.TrimSpace(s))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("configuration property %s wrong format: %v", key, err)
+	}
+	return result, nil
+}
+
+func EnvSizeSafe(key string) (int64, error) {
+	s, err := EnvSafe(key)
+	if err != nil {
+		return 0, err
+	}
+	result, err := parseSize(s)
+	if err != nil {
+		return 0, fmt.Errorf("configuration property %s wrong format: %v", key, err)
+	}
+	return result, nil
+}
+
 func Env(key string) string {
 	s, err := EnvSafe(key)
 	if err != nil {
@@ -63,19 +191,163 @@ func EnvBool(key string) bool {
 	return b
 }
 
-func EnvSlice(key string) []string {
+func EnvSliceSafe(key string) ([]string, error) {
+	s, err := EnvSafe(key)
+	if err != nil {
+		return nil, err
+	}
+	return parseSlice(s), nil
+}
+
+func EnvMapSafe(key string) (map[string]string, error) {
 	s, err := EnvSafe(key)
+	if err != nil {
+		return nil, err
+	}
+	return parseMap(key, s)
+}
+
+func EnvSlice(key string) []string {
+	s, err := EnvSliceSafe(key)
 	if err != nil {
 		log.Fatal(err)
 	}
-	return strings.Split(s, ",")
+	return s
 }
 
 func EnvMap(key string) map[string]string {
-	s, err := EnvSafe(key)
+	m, err := EnvMapSafe(key)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return m
+}
+
+func EnvInt64(key string) int64 {
+	i, err := EnvInt64Safe(key)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return i
+}
+
+func EnvFloat(key string) float64 {
+	f, err := EnvFloatSafe(key)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return f
+}
+
+func EnvDuration(key string) time.Duration {
+	d, err := EnvDurationSafe(key)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return d
+}
+
+func EnvURL(key string) *url.URL {
+	u, err := EnvURLSafe(key)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return u
+}
+
+func EnvRat(key string, scale int, mode RoundingMode) *big.Rat {
+	r, err := EnvRatSafe(key, scale, mode)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return r
+}
+
+func EnvTime(key string) time.Time {
+	t, err := EnvTimeSafe(key)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return t
+}
+
+func EnvSize(key string) int64 {
+	size, err := EnvSizeSafe(key)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return size
+}
+
+func EnvOr(key, defaultValue string) string {
+	return envOr(key, defaultValue, EnvSafe)
+}
+
+func EnvIntOr(key string, defaultValue int) int {
+	return envOr(key, defaultValue, EnvIntSafe)
+}
+
+func EnvBoolOr(key string, defaultValue bool) bool {
+	return envOr(key, defaultValue, EnvBoolSafe)
+}
+
+func EnvSliceOr(key string, defaultValue []string) []string {
+	return envOr(key, defaultValue, EnvSliceSafe)
+}
+
+func EnvMapOr(key string, defaultValue map[string]string) map[string]string {
+	return envOr(key, defaultValue, EnvMapSafe)
+}
+
+func EnvInt64Or(key string, defaultValue int64) int64 {
+	return envOr(key, defaultValue, EnvInt64Safe)
+}
+
+func EnvFloatOr(key string, defaultValue float64) float64 {
+	return envOr(key, defaultValue, EnvFloatSafe)
+}
+
+func EnvDurationOr(key string, defaultValue time.Duration) time.Duration {
+	return envOr(key, defaultValue, EnvDurationSafe)
+}
+
+func EnvURLOr(key string, defaultValue *url.URL) *url.URL {
+	return envOr(key, defaultValue, EnvURLSafe)
+}
+
+func EnvRatOr(key string, scale int, mode RoundingMode, defaultValue *big.Rat) *big.Rat {
+	return envOr(key, defaultValue, func(key string) (*big.Rat, error) {
+		return EnvRatSafe(key, scale, mode)
+	})
+}
+
+func EnvTimeOr(key string, defaultValue time.Time) time.Time {
+	return envOr(key, defaultValue, EnvTimeSafe)
+}
+
+func EnvSizeOr(key string, defaultValue int64) int64 {
+	return envOr(key, defaultValue, EnvSizeSafe)
+}
+
+// envOr returns defaultValue when the property is not set; a malformed value
+// is still fatal.
+func envOr[T any](key string, defaultValue T, get func(string) (T, error)) T {
+	v, err := get(key)
+	if errors.Is(err, ErrConfigNotSet) {
+		recordConfig(key, formatConfigDefault(defaultValue), "", true, false)
+		return defaultValue
+	}
 	if err != nil {
 		log.Fatal(err)
 	}
+	return v
+}
+
+func parseSlice(s string) []string {
+	return strings.Split(s, ",")
+}
+
+func parseMap(key, s string) (map[string]string, error) {
 	out := make(map[string]string)
 	for _, entry := range strings.Split(s, ",") {
 		entry = strings.TrimSpace(entry)
@@ -84,11 +356,56 @@ func EnvMap(key string) map[string]string {
 		}
 		kv := strings.SplitN(entry, "=", 2)
 		if len(kv) != 2 {
-			log.Fatalf("invalid %s entry %q, expected key=value", key, entry)
+			return nil, fmt.Errorf("invalid %s entry %q, expected key=value", key, entry)
 		}
 		k := strings.TrimSpace(kv[0])
 		v := strings.TrimSpace(kv[1])
 		out[k] = v
 	}
-	return out
+	return out, nil
+}
+
+var sizeUnits = map[string]int64{
+	"":    1,
+	"B":   1,
+	"K":   1 << 10,
+	"KB":  1 << 10,
+	"KIB": 1 << 10,
+	"M":   1 << 20,
+	"MB":  1 << 20,
+	"MIB": 1 << 20,
+	"G":   1 << 30,
+	"GB":  1 << 30,
+	"GIB": 1 << 30,
+	"T":   1 << 40,
+	"TB":  1 << 40,
+	"TIB": 1 << 40,
+}
+
+// parseSize parses sizes like "512", "10MB" or "1.5GiB"; units are binary, so
+// 1KB is 1024 bytes.
+func parseSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i < 0 {
+		i = len(s)
+	}
+	number, unit := s[:i], strings.ToUpper(strings.TrimSpace(s[i:]))
+
+	multiplier, ok := sizeUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("unknown size unit %q", unit)
+	}
+
+	value, ok := new(big.Rat).SetString(number)
+	if number == "" || !ok {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	value.Mul(value, new(big.Rat).SetInt64(multiplier))
+	if !value.IsInt() || !value.Num().IsInt64() {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return value.Num().Int64(), nil
 }
@@ -0,0 +1,199 @@
+package common
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationType        = reflect.TypeFor[time.Duration]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// LoadConfig fills the struct pointed to by cfg from configuration properties.
+//
+// Fields are mapped with the tags `env:"DB_PORT" default:"5432" required:"true"`;
+// `secret:"true"` masks the value in ConfigDump.
+// Nested structs without an env tag are loaded recursively, their keys prefixed
+// with the value of the optional `prefix:"DB_"` tag. Slices and maps follow the
+// EnvSlice and EnvMap formats. All missing or malformed properties are reported
+// together in the returned error.
+func LoadConfig(cfg any) error {
+	return LoadConfigFrom(CurrentConfigSource(), cfg)
+}
+
+func LoadConfigFrom(source ConfigSource, cfg any) error {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("configuration target must be a non-nil pointer to struct, got %T", cfg)
+	}
+
+	var errs []error
+	loadConfigStruct(source, v.Elem(), "", &errs)
+	return errors.Join(errs...)
+}
+
+func loadConfigStruct(source ConfigSource, v reflect.Value, prefix string, errs *[]error) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+
+		key, ok := field.Tag.Lookup("env")
+		if key == "-" {
+			continue
+		}
+		if !ok {
+			nestedPrefix := prefix + field.Tag.Get("prefix")
+			switch {
+			case field.Type.Kind() == reflect.Struct && !isConfigScalar(field.Type):
+				loadConfigStruct(source, fv, nestedPrefix, errs)
+			case field.Type.Kind() == reflect.Pointer && field.Type.Elem().Kind() == reflect.Struct && !isConfigScalar(field.Type.Elem()):
+				if fv.IsNil() {
+					fv.Set(reflect.New(field.Type.Elem()))
+				}
+				loadConfigStruct(source, fv.Elem(), nestedPrefix, errs)
+			}
+			continue
+		}
+		key = prefix + key
+		if secret, _ := strconv.ParseBool(field.Tag.Get("secret")); secret {
+			MarkConfigSecret(key)
+		}
+
+		value, err := envSafeFrom(source, key)
+		if err != nil && !errors.Is(err, ErrConfigNotSet) {
+			*errs = append(*errs, err)
+			continue
+		}
+		if err != nil {
+			if def, ok := field.Tag.Lookup("default"); ok {
+				value = def
+				recordConfig(key, value, "", true, false)
+			} else {
+				if required, _ := strconv.ParseBool(field.Tag.Get("required")); required {
+					*errs = append(*errs, err)
+				}
+				continue
+			}
+		}
+
+		if err := setConfigValue(fv, key, value); err != nil {
+			*errs = append(*errs, err)
+		}
+	}
+}
+
+func isConfigScalar(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+func unmarshalConfigText(v reflect.Value, key, raw string) error {
+	if err := v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(strings.TrimSpace(raw))); err != nil {
+		return fmt.Errorf("configuration property %s wrong format: %v", key, err)
+	}
+	return nil
+}
+
+func setConfigValue(v reflect.Value, key, raw string) error {
+	if v.Kind() == reflect.Pointer {
+		ptr := reflect.New(v.Type().Elem())
+		if err := setConfigValue(ptr.Elem(), key, raw); err != nil {
+			return err
+		}
+		v.Set(ptr)
+		return nil
+	}
+
+	if isConfigScalar(v.Type()) {
+		return unmarshalConfigText(v, key, raw)
+	}
+
+	switch v.Kind() {
+	case reflect.Slice:
+		parts := parseSlice(raw)
+		out := reflect.MakeSlice(v.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setConfigScalar(out.Index(i), key, part); err != nil {
+				return err
+			}
+		}
+		v.Set(out)
+		return nil
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("configuration property %s has unsupported type %s", key, v.Type())
+		}
+		entries, err := parseMap(key, raw)
+		if err != nil {
+			return err
+		}
+		out := reflect.MakeMapWithSize(v.Type(), len(entries))
+		for k, entry := range entries {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := setConfigScalar(elem, key, entry); err != nil {
+				return err
+			}
+			out.SetMapIndex(reflect.ValueOf(k).Convert(v.Type().Key()), elem)
+		}
+		v.Set(out)
+		return nil
+	default:
+		return setConfigScalar(v, key, raw)
+	}
+}
+
+func setConfigScalar(v reflect.Value, key, raw string) error {
+	if isConfigScalar(v.Type()) {
+		return unmarshalConfigText(v, key, raw)
+	}
+
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("configuration property %s wrong format: %v", key, err)
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	var err error
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		var b bool
+		if b, err = strconv.ParseBool(strings.TrimSpace(raw)); err == nil {
+			v.SetBool(b)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		if i, err = strconv.ParseInt(strings.TrimSpace(raw), 10, v.Type().Bits()); err == nil {
+			v.SetInt(i)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u uint64
+		if u, err = strconv.ParseUint(strings.TrimSpace(raw), 10, v.Type().Bits()); err == nil {
+			v.SetUint(u)
+		}
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		if f, err = strconv.ParseFloat(strings.TrimSpace(raw), v.Type().Bits()); err == nil {
+			v.SetFloat(f)
+		}
+	default:
+		return fmt.Errorf("configuration property %s has unsupported type %s", key, v.Type())
+	}
+	if err != nil {
+		return fmt.Errorf("configuration property %s wrong format: %v", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package common
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testDbConfig struct {
+	Host string `env:"HOST" default:"localhost"`
+	Port int    `env:"PORT" default:"5432"`
+}
+
+type testLoaderConfig struct {
+	Name     string            `env:"TEST_LOADER_NAME" required:"true"`
+	Debug    bool              `env:"TEST_LOADER_DEBUG"`
+	Timeout  time.Duration     `env:"TEST_LOADER_TIMEOUT" default:"5s"`
+	Ratio    float64           `env:"TEST_LOADER_RATIO"`
+	Max      uint16            `env:"TEST_LOADER_MAX"`
+	Tags     []string          `env:"TEST_LOADER_TAGS"`
+	Ports    []int             `env:"TEST_LOADER_PORTS"`
+	Labels   map[string]string `env:"TEST_LOADER_LABELS"`
+	Limit    *int64            `env:"TEST_LOADER_LIMIT"`
+	Ignored  string            `env:"-"`
+	Db       testDbConfig      `prefix:"TEST_LOADER_DB_"`
+	Replica  *testDbConfig     `prefix:"TEST_LOADER_REPLICA_"`
+	internal string
+}
+
+func TestLoadConfig_Success(t *testing.T) {
+	t.Setenv("TEST_LOADER_NAME", "service")
+	t.Setenv("TEST_LOADER_DEBUG", "true")
+	t.Setenv("TEST_LOADER_RATIO", "0.25")
+	t.Setenv("TEST_LOADER_MAX", "300")
+	t.Setenv("TEST_LOADER_TAGS", "a,b,c")
+	t.Setenv("TEST_LOADER_PORTS", "80, 443")
+	t.Setenv("TEST_LOADER_LABELS", "env=dev, team = core")
+	t.Setenv("TEST_LOADER_LIMIT", "42")
+	t.Setenv("TEST_LOADER_DB_HOST", "db.local")
+	t.Setenv("TEST_LOADER_REPLICA_PORT", "6432")
+
+	var cfg testLoaderConfig
+	require.NoError(t, LoadConfig(&cfg))
+
+	assert.Equal(t, "service", cfg.Name)
+	assert.True(t, cfg.Debug)
+	assert.Equal(t, 5*time.Second, cfg.Timeout)
+	assert.Equal(t, 0.25, cfg.Ratio)
+	assert.Equal(t, uint16(300), cfg.Max)
+	assert.Equal(t, []string{"a", "b", "c"}, cfg.Tags)
+	assert.Equal(t, []int{80, 443}, cfg.Ports)
+	assert.Equal(t, map[string]string{"env": "dev", "team": "core"}, cfg.Labels)
+	require.NotNil(t, cfg.Limit)
+	assert.Equal(t, int64(42), *cfg.Limit)
+	assert.Equal(t, testDbConfig{Host: "db.local", Port: 5432}, cfg.Db)
+	require.NotNil(t, cfg.Replica)
+	assert.Equal(t, testDbConfig{Host: "localhost", Port: 6432}, *cfg.Replica)
+}
+
+func TestLoadConfig_AggregatesErrors(t *testing.T) {
+	t.Setenv("TEST_LOADER_TIMEOUT", "soon")
+	t.Setenv("TEST_LOADER_PORTS", "80,http")
+	t.Setenv("TEST_LOADER_LABELS", "broken")
+	t.Setenv("TEST_LOADER_DB_PORT", "x")
+
+	var cfg testLoaderConfig
+	err := LoadConfig(&cfg)
+	require.Error(t, err)
+
+	msg := err.Error()
+	for _, key := range []string{
+		"TEST_LOADER_NAME not set",
+		"TEST_LOADER_TIMEOUT wrong format",
+		"TEST_LOADER_PORTS wrong format",
+		"invalid TEST_LOADER_LABELS entry",
+		"TEST_LOADER_DB_PORT wrong format",
+	} {
+		assert.Contains(t, msg, key)
+	}
+	assert.Len(t, strings.Split(msg, "\n"), 5)
+}
+
+func TestLoadConfig_TextUnmarshaler(t *testing.T) {
+	t.Setenv("TEST_LOADER_STARTED", "2025-01-02T03:04:05Z")
+
+	var cfg struct {
+		Started time.Time `env:"TEST_LOADER_STARTED"`
+	}
+	require.NoError(t, LoadConfig(&cfg))
+	assert.Equal(t, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), cfg.Started)
+}
+
+func TestLoadConfig_InvalidTarget(t *testing.T) {
+	var cfg testLoaderConfig
+	assert.Error(t, LoadConfig(cfg))
+	assert.Error(t, LoadConfig((*testLoaderConfig)(nil)))
+}
+
+func TestEnvSliceSafe_Missing(t *testing.T) {
+	_, err := EnvSliceSafe("TEST_ENV_SLICE_MISSING")
+	assert.Error(t, err)
+}
+
+func TestEnvMapSafe_InvalidEntry(t *testing.T) {
+	t.Setenv("TEST_ENV_MAP_SAFE_INVALID", "a=1,broken")
+
+	_, err := EnvMapSafe("TEST_ENV_MAP_SAFE_INVALID")
+	assert.Error(t, err)
+}
@@ -0,0 +1,243 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const redactedConfigValue = "******"
+
+type ConfigEntry struct {
+	Key       string
+	Value     string
+	Source    string
+	Defaulted bool
+	Secret    bool
+}
+
+// ConfigRule validates a property value. Blank values pass every rule except
+// ConfigNotEmpty, so optional properties are only checked when set.
+type ConfigRule func(value string) error
+
+var configRegistry = struct {
+	sync.Mutex
+	entries map[string]ConfigEntry
+	secrets map[string]struct{}
+	rules   map[string][]ConfigRule
+}{
+	entries: make(map[string]ConfigEntry),
+	secrets: make(map[string]struct{}),
+	rules:   make(map[string][]ConfigRule),
+}
+
+var secretConfigKeyParts = []string{"PASSWORD", "PASSWD", "SECRET", "TOKEN", "PRIVATE_KEY", "API_KEY", "CREDENTIAL"}
+
+func recordConfig(key, value, source string, defaulted, secret bool) {
+	configRegistry.Lock()
+	defer configRegistry.Unlock()
+	if existing, ok := configRegistry.entries[key]; ok && existing.Secret {
+		secret = true
+	}
+	configRegistry.entries[key] = ConfigEntry{
+		Key:       key,
+		Value:     value,
+		Source:    source,
+		Defaulted: defaulted,
+		Secret:    secret,
+	}
+}
+
+func formatConfigDefault(value any) string {
+	switch v := value.(type) {
+	case *big.Rat:
+		if v == nil {
+			return ""
+		}
+		if n, exact := v.FloatPrec(); exact {
+			return v.FloatString(n)
+		}
+		return v.RatString()
+	case *url.URL:
+		if v == nil {
+			return ""
+		}
+		return v.String()
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case []string:
+		return strings.Join(v, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// MarkConfigSecret masks the given keys in ConfigDump. Keys containing
+// PASSWORD, SECRET, TOKEN and similar words are masked without marking.
+func MarkConfigSecret(keys ...string) {
+	configRegistry.Lock()
+	defer configRegistry.Unlock()
+	for _, key := range keys {
+		configRegistry.secrets[key] = struct{}{}
+	}
+}
+
+func IsConfigSecret(key string) bool {
+	configRegistry.Lock()
+	defer configRegistry.Unlock()
+	return isConfigSecretLocked(key)
+}
+
+func isConfigSecretLocked(key string) bool {
+	if _, ok := configRegistry.secrets[key]; ok {
+		return true
+	}
+	if entry, ok := configRegistry.entries[key]; ok && entry.Secret {
+		return true
+	}
+	upper := strings.ToUpper(key)
+	for _, part := range secretConfigKeyParts {
+		if strings.Contains(upper, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// ConfigEntries returns every property read so far, sorted by key, with
+// secret values masked.
+func ConfigEntries() []ConfigEntry {
+	configRegistry.Lock()
+	defer configRegistry.Unlock()
+
+	result := make([]ConfigEntry, 0, len(configRegistry.entries))
+	for key, entry := range configRegistry.entries {
+		entry.Secret = isConfigSecretLocked(key)
+		if entry.Secret && entry.Value != "" {
+			entry.Value = redactedConfigValue
+		}
+		result = append(result, entry)
+	}
+	slices.SortFunc(result, func(a, b ConfigEntry) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return result
+}
+
+func ConfigDump() string {
+	var sb strings.Builder
+	for _, entry := range ConfigEntries() {
+		var source string
+		switch {
+		case entry.Defaulted:
+			source = "default"
+		case entry.Source == "":
+			source = "unset"
+		default:
+			source = entry.Source
+		}
+		fmt.Fprintf(&sb, "%s=%s (%s)\n", entry.Key, entry.Value, source)
+	}
+	return sb.String()
+}
+
+func ResetConfigRegistry() {
+	configRegistry.Lock()
+	defer configRegistry.Unlock()
+	clear(configRegistry.entries)
+	clear(configRegistry.secrets)
+	clear(configRegistry.rules)
+}
+
+func AddConfigRules(key string, rules ...ConfigRule) {
+	configRegistry.Lock()
+	defer configRegistry.Unlock()
+	configRegistry.rules[key] = append(configRegistry.rules[key], rules...)
+}
+
+// ValidateConfig checks every key with registered rules against the value
+// read so far, or against the current source when the key was never read.
+func ValidateConfig() error {
+	configRegistry.Lock()
+	keys := make([]string, 0, len(configRegistry.rules))
+	for key := range configRegistry.rules {
+		keys = append(keys, key)
+	}
+	configRegistry.Unlock()
+	slices.Sort(keys)
+
+	var errs []error
+	for _, key := range keys {
+		configRegistry.Lock()
+		entry, read := configRegistry.entries[key]
+		rules := slices.Clone(configRegistry.rules[key])
+		configRegistry.Unlock()
+
+		value := entry.Value
+		if !read {
+			v, err := EnvSafe(key)
+			if err != nil && !errors.Is(err, ErrConfigNotSet) {
+				errs = append(errs, err)
+				continue
+			}
+			value = v
+		}
+
+		for _, rule := range rules {
+			if err := rule(value); err != nil {
+				errs = append(errs, fmt.Errorf("configuration property %s invalid: %w", key, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func ConfigNotEmpty() ConfigRule {
+	return func(value string) error {
+		if IsBlank(value) {
+			return errors.New("must not be empty")
+		}
+		return nil
+	}
+}
+
+func ConfigRange(min, max float64) ConfigRule {
+	return func(value string) error {
+		if IsBlank(value) {
+			return nil
+		}
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return errors.New("not a number")
+		}
+		if f < min || f > max {
+			return fmt.Errorf("must be between %v and %v", min, max)
+		}
+		return nil
+	}
+}
+
+func ConfigOneOf(allowed ...string) ConfigRule {
+	return func(value string) error {
+		if IsBlank(value) || slices.Contains(allowed, strings.TrimSpace(value)) {
+			return nil
+		}
+		return fmt.Errorf("must be one of %s", strings.Join(allowed, ", "))
+	}
+}
+
+func ConfigMatches(pattern *regexp.Regexp) ConfigRule {
+	return func(value string) error {
+		if IsBlank(value) || pattern.MatchString(value) {
+			return nil
+		}
+		return fmt.Errorf("does not match %s", pattern)
+	}
+}
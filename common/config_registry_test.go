@@ -0,0 +1,124 @@
+package common
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func resetConfigRegistry(t *testing.T) {
+	t.Helper()
+	ResetConfigRegistry()
+	t.Cleanup(ResetConfigRegistry)
+}
+
+func findConfigEntry(t *testing.T, key string) ConfigEntry {
+	t.Helper()
+	for _, entry := range ConfigEntries() {
+		if entry.Key == key {
+			return entry
+		}
+	}
+	t.Fatalf("config entry %s not recorded", key)
+	return ConfigEntry{}
+}
+
+func TestConfigRegistry_RecordsReads(t *testing.T) {
+	resetConfigRegistry(t)
+	useConfigSource(t, NewLayeredSource(
+		NewMapSource("flags", map[string]string{"TEST_REG_PORT": "8080"}),
+		NewEnvSource(),
+	))
+	t.Setenv("TEST_REG_NAME", "demo")
+
+	_ = Env("TEST_REG_NAME")
+	_ = EnvInt("TEST_REG_PORT")
+	_ = EnvDurationOr("TEST_REG_TIMEOUT", 0)
+	_, _ = EnvSafe("TEST_REG_MISSING")
+
+	assert.Equal(t, ConfigEntry{Key: "TEST_REG_NAME", Value: "demo", Source: "env"}, findConfigEntry(t, "TEST_REG_NAME"))
+	assert.Equal(t, ConfigEntry{Key: "TEST_REG_PORT", Value: "8080", Source: "flags"}, findConfigEntry(t, "TEST_REG_PORT"))
+	assert.Equal(t, ConfigEntry{Key: "TEST_REG_TIMEOUT", Value: "0s", Defaulted: true}, findConfigEntry(t, "TEST_REG_TIMEOUT"))
+	assert.Equal(t, ConfigEntry{Key: "TEST_REG_MISSING"}, findConfigEntry(t, "TEST_REG_MISSING"))
+}
+
+func TestConfigRegistry_LoaderDefaultsAndSecrets(t *testing.T) {
+	resetConfigRegistry(t)
+
+	var cfg struct {
+		Host   string `env:"TEST_REG_HOST" default:"localhost"`
+		ApiKey string `env:"TEST_REG_APIKEY" default:"dev" secret:"true"`
+	}
+	require.NoError(t, LoadConfig(&cfg))
+
+	assert.Equal(t, ConfigEntry{Key: "TEST_REG_HOST", Value: "localhost", Defaulted: true}, findConfigEntry(t, "TEST_REG_HOST"))
+	assert.Equal(t, ConfigEntry{Key: "TEST_REG_APIKEY", Value: "******", Defaulted: true, Secret: true}, findConfigEntry(t, "TEST_REG_APIKEY"))
+}
+
+func TestConfigDump_Redacted(t *testing.T) {
+	resetConfigRegistry(t)
+	t.Setenv("TEST_REG_DB_PASSWORD", "hunter2")
+	t.Setenv("TEST_REG_DB_USER", "app")
+	t.Setenv("TEST_REG_CUSTOM", "hidden")
+	MarkConfigSecret("TEST_REG_CUSTOM")
+
+	_ = Env("TEST_REG_DB_PASSWORD")
+	_ = Env("TEST_REG_DB_USER")
+	_ = Env("TEST_REG_CUSTOM")
+	_ = EnvOr("TEST_REG_REGION", "eu")
+
+	dump := ConfigDump()
+	assert.Equal(t, "TEST_REG_CUSTOM=****** (env)\n"+
+		"TEST_REG_DB_PASSWORD=****** (env)\n"+
+		"TEST_REG_DB_USER=app (env)\n"+
+		"TEST_REG_REGION=eu (default)\n", dump)
+	assert.NotContains(t, dump, "hunter2")
+	assert.NotContains(t, dump, "hidden")
+}
+
+func TestConfigDump_SecretFileMasked(t *testing.T) {
+	resetConfigRegistry(t)
+	path := writeSecretFile(t, "from-file", 0o600)
+	t.Setenv("TEST_REG_CERT_FILE", path)
+
+	_ = Env("TEST_REG_CERT")
+
+	entry := findConfigEntry(t, "TEST_REG_CERT")
+	assert.True(t, entry.Secret)
+	assert.Equal(t, path, entry.Source)
+	assert.NotContains(t, ConfigDump(), "from-file")
+}
+
+func TestValidateConfig(t *testing.T) {
+	resetConfigRegistry(t)
+	t.Setenv("TEST_REG_WORKERS", "64")
+	t.Setenv("TEST_REG_MODE", "fast")
+	t.Setenv("TEST_REG_REGION", "eu-west")
+
+	_ = EnvInt("TEST_REG_WORKERS")
+
+	AddConfigRules("TEST_REG_WORKERS", ConfigRange(1, 32))
+	AddConfigRules("TEST_REG_MODE", ConfigOneOf("safe", "strict"))
+	AddConfigRules("TEST_REG_REGION", ConfigMatches(regexp.MustCompile(`^[a-z]{2}-[a-z]+$`)))
+	AddConfigRules("TEST_REG_OWNER", ConfigNotEmpty())
+	AddConfigRules("TEST_REG_OPTIONAL", ConfigRange(0, 1))
+
+	err := ValidateConfig()
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "TEST_REG_WORKERS invalid: must be between 1 and 32")
+	assert.ErrorContains(t, err, "TEST_REG_MODE invalid: must be one of safe, strict")
+	assert.ErrorContains(t, err, "TEST_REG_OWNER invalid: must not be empty")
+	assert.NotContains(t, err.Error(), "TEST_REG_REGION")
+	assert.NotContains(t, err.Error(), "TEST_REG_OPTIONAL")
+}
+
+func TestValidateConfig_Valid(t *testing.T) {
+	resetConfigRegistry(t)
+	t.Setenv("TEST_REG_LEVEL", "info")
+
+	AddConfigRules("TEST_REG_LEVEL", ConfigNotEmpty(), ConfigOneOf("debug", "info"))
+
+	assert.NoError(t, ValidateConfig())
+}
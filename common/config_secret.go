@@ -0,0 +1,66 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// A property KEY that is not set is read from the file named by KEY_FILE, the
+// convention used for Docker and Kubernetes secrets.
+const secretFileSuffix = "_FILE"
+
+var (
+	secretFileMu         sync.Mutex
+	secretFileCache      = make(map[string]string)
+	secretFileWorldReads = false
+)
+
+// AllowWorldReadableSecretFiles disables the check refusing secret files that
+// any user on the host can read.
+func AllowWorldReadableSecretFiles(allow bool) {
+	secretFileMu.Lock()
+	defer secretFileMu.Unlock()
+	secretFileWorldReads = allow
+}
+
+func ClearSecretFileCache() {
+	secretFileMu.Lock()
+	defer secretFileMu.Unlock()
+	clear(secretFileCache)
+}
+
+func readSecretFile(key, path string) (string, error) {
+	secretFileMu.Lock()
+	defer secretFileMu.Unlock()
+
+	if value, ok := secretFileCache[path]; ok {
+		return value, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("configuration property %s secret file %s: %w", key, path, err)
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("configuration property %s secret file %s is a directory", key, path)
+	}
+	if !secretFileWorldReads && runtime.GOOS != "windows" && info.Mode().Perm()&0o004 != 0 {
+		return "", fmt.Errorf("configuration property %s secret file %s is world-readable (%s)", key, path, info.Mode().Perm())
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("configuration property %s secret file %s: %w", key, path, err)
+	}
+
+	value := strings.TrimSpace(string(data))
+	if value == "" {
+		return "", fmt.Errorf("configuration property %s secret file %s is empty", key, path)
+	}
+
+	secretFileCache[path] = value
+	return value, nil
+}
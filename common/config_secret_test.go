@@ -0,0 +1,92 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSecretFile(t *testing.T, content string, perm os.FileMode) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(path, []byte(content), perm))
+	require.NoError(t, os.Chmod(path, perm))
+	t.Cleanup(ClearSecretFileCache)
+	return path
+}
+
+func TestEnvSafe_SecretFile(t *testing.T) {
+	path := writeSecretFile(t, "  s3cr3t\n", 0o600)
+	t.Setenv("TEST_SECRET_PASSWORD_FILE", path)
+
+	got, err := EnvSafe("TEST_SECRET_PASSWORD")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t", got)
+}
+
+func TestEnvSafe_SecretFile_LiteralValueWins(t *testing.T) {
+	path := writeSecretFile(t, "from-file", 0o600)
+	t.Setenv("TEST_SECRET_LITERAL", "literal")
+	t.Setenv("TEST_SECRET_LITERAL_FILE", path)
+
+	assert.Equal(t, "literal", Env("TEST_SECRET_LITERAL"))
+}
+
+func TestEnvSafe_SecretFile_Cached(t *testing.T) {
+	path := writeSecretFile(t, "first", 0o600)
+	t.Setenv("TEST_SECRET_CACHED_FILE", path)
+
+	assert.Equal(t, "first", Env("TEST_SECRET_CACHED"))
+	require.NoError(t, os.WriteFile(path, []byte("second"), 0o600))
+	assert.Equal(t, "first", Env("TEST_SECRET_CACHED"))
+
+	ClearSecretFileCache()
+	assert.Equal(t, "second", Env("TEST_SECRET_CACHED"))
+}
+
+func TestEnvSafe_SecretFile_TypedGetters(t *testing.T) {
+	path := writeSecretFile(t, "5432", 0o400)
+	t.Setenv("TEST_SECRET_PORT_FILE", path)
+
+	assert.Equal(t, 5432, EnvInt("TEST_SECRET_PORT"))
+}
+
+func TestEnvSafe_SecretFile_Errors(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing")
+	t.Setenv("TEST_SECRET_MISSING_FILE", missing)
+	_, err := EnvSafe("TEST_SECRET_MISSING")
+	assert.ErrorContains(t, err, missing)
+	assert.NotErrorIs(t, err, ErrConfigNotSet)
+
+	worldReadable := writeSecretFile(t, "value", 0o644)
+	t.Setenv("TEST_SECRET_WORLD_FILE", worldReadable)
+	_, err = EnvSafe("TEST_SECRET_WORLD")
+	assert.ErrorContains(t, err, "world-readable")
+
+	empty := writeSecretFile(t, " \n", 0o600)
+	t.Setenv("TEST_SECRET_EMPTY_FILE", empty)
+	_, err = EnvSafe("TEST_SECRET_EMPTY")
+	assert.ErrorContains(t, err, "is empty")
+}
+
+func TestEnvSafe_SecretFile_AllowWorldReadable(t *testing.T) {
+	AllowWorldReadableSecretFiles(true)
+	t.Cleanup(func() { AllowWorldReadableSecretFiles(false) })
+
+	path := writeSecretFile(t, "shared", 0o644)
+	t.Setenv("TEST_SECRET_SHARED_FILE", path)
+
+	assert.Equal(t, "shared", Env("TEST_SECRET_SHARED"))
+}
+
+func TestLoadConfig_SecretFileError(t *testing.T) {
+	t.Setenv("TEST_SECRET_LOADER_FILE", filepath.Join(t.TempDir(), "missing"))
+
+	var cfg struct {
+		Password string `env:"TEST_SECRET_LOADER" default:"ignored"`
+	}
+	assert.Error(t, LoadConfig(&cfg))
+}
@@ -0,0 +1,290 @@
+package common
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+type ConfigSource interface {
+	Name() string
+	Lookup(key string) (string, bool)
+}
+
+var (
+	configSourceMu sync.RWMutex
+	configSource   ConfigSource = NewEnvSource()
+)
+
+// SetConfigSource replaces the source used by the Env* getters and LoadConfig.
+// Passing nil restores the process environment.
+func SetConfigSource(source ConfigSource) {
+	if source == nil {
+		source = NewEnvSource()
+	}
+	configSourceMu.Lock()
+	defer configSourceMu.Unlock()
+	configSource = source
+}
+
+func CurrentConfigSource() ConfigSource {
+	configSourceMu.RLock()
+	defer configSourceMu.RUnlock()
+	return configSource
+}
+
+type envSource struct{}
+
+func NewEnvSource() ConfigSource {
+	return envSource{}
+}
+
+func (envSource) Name() string {
+	return "env"
+}
+
+func (envSource) Lookup(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+type mapSource struct {
+	name   string
+	values map[string]string
+}
+
+func NewMapSource(name string, values map[string]string) ConfigSource {
+	copied := make(map[string]string, len(values))
+	for k, v := range values {
+		copied[k] = v
+	}
+	return &mapSource{name: name, values: copied}
+}
+
+func (ms *mapSource) Name() string {
+	return ms.name
+}
+
+func (ms *mapSource) Lookup(key string) (string, bool) {
+	v, ok := ms.values[key]
+	return v, ok
+}
+
+func NewDotEnvSource(path string) (ConfigSource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dotenv file %s: %w", path, err)
+	}
+
+	values, err := parseDotEnv(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse dotenv file %s: %w", path, err)
+	}
+	return &mapSource{name: path, values: values}, nil
+}
+
+func parseDotEnv(data []byte) (map[string]string, error) {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", lineNo)
+		}
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return nil, fmt.Errorf("line %d: empty key", lineNo)
+		}
+
+		value, err := parseDotEnvValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		values[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func parseDotEnvValue(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, `"`):
+		var sb strings.Builder
+		for i := 1; i < len(value); i++ {
+			c := value[i]
+			switch c {
+			case '\\':
+				if i+1 == len(value) {
+					return "", fmt.Errorf("unterminated escape in %s", value)
+				}
+				i++
+				switch value[i] {
+				case 'n':
+					sb.WriteByte('\n')
+				case 't':
+					sb.WriteByte('\t')
+				case 'r':
+					sb.WriteByte('\r')
+				default:
+					sb.WriteByte(value[i])
+				}
+			case '"':
+				return sb.String(), nil
+			default:
+				sb.WriteByte(c)
+			}
+		}
+		return "", fmt.Errorf("unterminated quoted value %s", value)
+	case strings.HasPrefix(value, "'"):
+		end := strings.IndexByte(value[1:], '\'')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated quoted value %s", value)
+		}
+		return value[1 : end+1], nil
+	default:
+		if i := strings.Index(value, " #"); i >= 0 {
+			value = value[:i]
+		}
+		return strings.TrimSpace(value), nil
+	}
+}
+
+// NewFileSource reads a YAML (.yaml, .yml) or JSON (.json) file. Nested keys are
+// flattened to upper case joined by underscores, so db.port becomes DB_PORT, and
+// lists are joined by commas.
+func NewFileSource(path string) (ConfigSource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read configuration file %s: %w", path, err)
+	}
+
+	var raw map[string]any
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		err = decoder.Decode(&raw)
+	default:
+		return nil, fmt.Errorf("unsupported configuration file type %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse configuration file %s: %w", path, err)
+	}
+
+	values := make(map[string]string)
+	flattenConfig("", raw, values)
+	return &mapSource{name: path, values: values}, nil
+}
+
+func flattenConfig(prefix string, value any, out map[string]string) {
+	switch v := value.(type) {
+	case map[string]any:
+		for k, child := range v {
+			flattenConfig(joinConfigKey(prefix, k), child, out)
+		}
+	case []any:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, fmt.Sprint(item))
+		}
+		out[prefix] = strings.Join(parts, ",")
+	case nil:
+		out[prefix] = ""
+	default:
+		out[prefix] = fmt.Sprint(v)
+	}
+}
+
+func joinConfigKey(prefix, key string) string {
+	key = strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(key))
+	if prefix == "" {
+		return key
+	}
+	return prefix + "_" + key
+}
+
+type flagSource struct {
+	flagSet *flag.FlagSet
+}
+
+// NewFlagSource exposes flags explicitly set on the command line. The key
+// DB_PORT resolves to the flag db-port, or to a flag named exactly DB_PORT.
+func NewFlagSource(flagSet *flag.FlagSet) ConfigSource {
+	return &flagSource{flagSet: flagSet}
+}
+
+func (fs *flagSource) Name() string {
+	return "flags"
+}
+
+func (fs *flagSource) Lookup(key string) (string, bool) {
+	names := []string{key, strings.ToLower(strings.ReplaceAll(key, "_", "-"))}
+
+	var result string
+	found := false
+	fs.flagSet.Visit(func(f *flag.Flag) {
+		if found {
+			return
+		}
+		for _, name := range names {
+			if f.Name == name {
+				result = f.Value.String()
+				found = true
+				return
+			}
+		}
+	})
+	return result, found
+}
+
+type LayeredSource struct {
+	sources []ConfigSource
+}
+
+// NewLayeredSource combines sources in order of precedence: the first source
+// holding a non-blank value for a key wins. A typical stack is flags, env,
+// dotenv file and configuration file.
+func NewLayeredSource(sources ...ConfigSource) *LayeredSource {
+	return &LayeredSource{sources: sources}
+}
+
+func (ls *LayeredSource) Name() string {
+	names := make([]string, 0, len(ls.sources))
+	for _, source := range ls.sources {
+		names = append(names, source.Name())
+	}
+	return strings.Join(names, ",")
+}
+
+func (ls *LayeredSource) Lookup(key string) (string, bool) {
+	value, _, ok := ls.Resolve(key)
+	return value, ok
+}
+
+func (ls *LayeredSource) Resolve(key string) (string, ConfigSource, bool) {
+	for _, source := range ls.sources {
+		if value, ok := source.Lookup(key); ok && !IsBlank(value) {
+			return value, source, true
+		}
+	}
+	return "", nil, false
+}
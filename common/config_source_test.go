@@ -0,0 +1,180 @@
+package common
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func useConfigSource(t *testing.T, source ConfigSource) {
+	t.Helper()
+	SetConfigSource(source)
+	t.Cleanup(func() { SetConfigSource(nil) })
+}
+
+func TestDotEnvSource(t *testing.T) {
+	path := writeTestFile(t, ".env", `
+# comment
+APP_NAME=demo
+export APP_PORT = 8080
+APP_GREETING="hello\nworld" # trailing
+APP_RAW='no $expansion # here'
+APP_INLINE=value # comment
+APP_EMPTY=
+`)
+
+	source, err := NewDotEnvSource(path)
+	require.NoError(t, err)
+
+	tests := map[string]string{
+		"APP_NAME":     "demo",
+		"APP_PORT":     "8080",
+		"APP_GREETING": "hello\nworld",
+		"APP_RAW":      "no $expansion # here",
+		"APP_INLINE":   "value",
+		"APP_EMPTY":    "",
+	}
+	for key, want := range tests {
+		got, ok := source.Lookup(key)
+		assert.True(t, ok, key)
+		assert.Equal(t, want, got, key)
+	}
+
+	_, ok := source.Lookup("APP_MISSING")
+	assert.False(t, ok)
+}
+
+func TestDotEnvSource_Invalid(t *testing.T) {
+	_, err := NewDotEnvSource(writeTestFile(t, ".env", "VALID=1\nbroken\n"))
+	assert.ErrorContains(t, err, "line 2")
+
+	_, err = NewDotEnvSource(writeTestFile(t, ".env", `KEY="open`))
+	assert.Error(t, err)
+
+	_, err = NewDotEnvSource(filepath.Join(t.TempDir(), "missing.env"))
+	assert.Error(t, err)
+}
+
+func TestFileSource_Yaml(t *testing.T) {
+	path := writeTestFile(t, "config.yaml", `
+app:
+  name: demo
+  log-level: debug
+db:
+  port: 5432
+  hosts: [a, b]
+`)
+
+	source, err := NewFileSource(path)
+	require.NoError(t, err)
+
+	tests := map[string]string{
+		"APP_NAME":      "demo",
+		"APP_LOG_LEVEL": "debug",
+		"DB_PORT":       "5432",
+		"DB_HOSTS":      "a,b",
+	}
+	for key, want := range tests {
+		got, ok := source.Lookup(key)
+		assert.True(t, ok, key)
+		assert.Equal(t, want, got, key)
+	}
+}
+
+func TestFileSource_Json(t *testing.T) {
+	path := writeTestFile(t, "config.json", `{"db": {"port": 5432, "ratio": 0.5, "ssl": true}}`)
+
+	source, err := NewFileSource(path)
+	require.NoError(t, err)
+
+	port, _ := source.Lookup("DB_PORT")
+	ratio, _ := source.Lookup("DB_RATIO")
+	ssl, _ := source.Lookup("DB_SSL")
+	assert.Equal(t, "5432", port)
+	assert.Equal(t, "0.5", ratio)
+	assert.Equal(t, "true", ssl)
+}
+
+func TestFileSource_UnsupportedType(t *testing.T) {
+	_, err := NewFileSource(writeTestFile(t, "config.toml", "a = 1"))
+	assert.Error(t, err)
+}
+
+func TestFlagSource(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Int("db-port", 5432, "")
+	fs.String("APP_NAME", "", "")
+	fs.String("unused", "default", "")
+	require.NoError(t, fs.Parse([]string{"-db-port=6543", "-APP_NAME=demo"}))
+
+	source := NewFlagSource(fs)
+
+	port, ok := source.Lookup("DB_PORT")
+	assert.True(t, ok)
+	assert.Equal(t, "6543", port)
+
+	name, ok := source.Lookup("APP_NAME")
+	assert.True(t, ok)
+	assert.Equal(t, "demo", name)
+
+	_, ok = source.Lookup("UNUSED")
+	assert.False(t, ok, "flags left at their defaults are not reported")
+}
+
+func TestLayeredSource_Precedence(t *testing.T) {
+	high := NewMapSource("high", map[string]string{"A": "high", "B": " "})
+	low := NewMapSource("low", map[string]string{"A": "low", "B": "low", "C": "low"})
+	layered := NewLayeredSource(high, low)
+
+	a, source, ok := layered.Resolve("A")
+	assert.True(t, ok)
+	assert.Equal(t, "high", a)
+	assert.Equal(t, "high", source.Name())
+
+	b, source, ok := layered.Resolve("B")
+	assert.True(t, ok)
+	assert.Equal(t, "low", b, "blank values fall through to lower layers")
+	assert.Equal(t, "low", source.Name())
+
+	_, _, ok = layered.Resolve("D")
+	assert.False(t, ok)
+
+	assert.Equal(t, "high,low", layered.Name())
+}
+
+func TestSetConfigSource_EnvGettersResolveThroughSource(t *testing.T) {
+	useConfigSource(t, NewLayeredSource(
+		NewMapSource("override", map[string]string{"TEST_SOURCE_PORT": "9090"}),
+		NewEnvSource(),
+	))
+	t.Setenv("TEST_SOURCE_PORT", "8080")
+	t.Setenv("TEST_SOURCE_NAME", "from-env")
+
+	assert.Equal(t, 9090, EnvInt("TEST_SOURCE_PORT"))
+	assert.Equal(t, "from-env", Env("TEST_SOURCE_NAME"))
+
+	var cfg struct {
+		Port int `env:"TEST_SOURCE_PORT"`
+	}
+	require.NoError(t, LoadConfig(&cfg))
+	assert.Equal(t, 9090, cfg.Port)
+}
+
+func TestLoadConfigFrom(t *testing.T) {
+	var cfg struct {
+		Name string `env:"NAME" required:"true"`
+	}
+	require.NoError(t, LoadConfigFrom(NewMapSource("test", map[string]string{"NAME": "demo"}), &cfg))
+	assert.Equal(t, "demo", cfg.Name)
+}
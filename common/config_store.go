@@ -0,0 +1,231 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+type ConfigChange[T any] struct {
+	Old T
+	New T
+}
+
+// ConfigStore holds a configuration snapshot loaded with LoadConfigFrom. On
+// reload the sources are rebuilt, the new snapshot is validated and swapped in
+// atomically; a failed reload keeps the previous snapshot.
+type ConfigStore[T any] struct {
+	sources  func() (ConfigSource, error)
+	validate func(*T) error
+
+	current atomic.Pointer[T]
+	// reloadMu serializes loading and swapping; callbacks run without locks so
+	// they may call back into the store.
+	reloadMu sync.Mutex
+	swaps    uint64
+
+	mu          sync.Mutex
+	subscribers map[int]func(ConfigChange[T])
+	nextID      int
+	onError     func(error)
+	// One Reload at a time delivers changes; overlapping reloads leave their
+	// change in pending, a newer swap replacing an older one.
+	dispatching bool
+	pending     *ConfigChange[T]
+	pendingSwap uint64
+	delivered   uint64
+}
+
+func NewConfigStore[T any](sources func() (ConfigSource, error), validate func(*T) error) (*ConfigStore[T], error) {
+	store := &ConfigStore[T]{
+		sources:     sources,
+		validate:    validate,
+		subscribers: make(map[int]func(ConfigChange[T])),
+	}
+
+	cfg, err := store.load()
+	if err != nil {
+		return nil, err
+	}
+	store.current.Store(cfg)
+	return store, nil
+}
+
+func (s *ConfigStore[T]) Get() T {
+	return *s.current.Load()
+}
+
+func (s *ConfigStore[T]) Subscribe(fn func(ConfigChange[T])) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.nextID
+	s.nextID++
+	s.subscribers[id] = fn
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.subscribers, id)
+	}
+}
+
+// OnError registers a callback for failed reloads triggered by the watchers.
+func (s *ConfigStore[T]) OnError(fn func(error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onError = fn
+}
+
+// Reload loads and swaps in a new snapshot and notifies the subscribers.
+// Subscribers see changes in swap order and the last New they receive is the
+// current snapshot; if reloads overlap, intermediate changes are merged and
+// may be delivered by the other Reload call.
+func (s *ConfigStore[T]) Reload() error {
+	change, swap, err := s.swap()
+	if err != nil {
+		s.mu.Lock()
+		onError := s.onError
+		s.mu.Unlock()
+		if onError != nil {
+			onError(err)
+		}
+		return err
+	}
+
+	s.mu.Lock()
+	if swap > s.pendingSwap && swap > s.delivered {
+		if s.pending != nil {
+			change.Old = s.pending.Old
+		}
+		s.pending, s.pendingSwap = &change, swap
+	}
+	if s.dispatching {
+		s.mu.Unlock()
+		return nil
+	}
+	s.dispatching = true
+	completed := false
+	defer func() {
+		// A panicking subscriber must not stop later deliveries.
+		if !completed {
+			s.mu.Lock()
+			s.dispatching = false
+			s.mu.Unlock()
+		}
+	}()
+	for s.pending != nil {
+		next := *s.pending
+		s.delivered, s.pending = s.pendingSwap, nil
+		subscribers := make([]func(ConfigChange[T]), 0, len(s.subscribers))
+		for _, id := range slices.Sorted(maps.Keys(s.subscribers)) {
+			subscribers = append(subscribers, s.subscribers[id])
+		}
+		s.mu.Unlock()
+
+		for _, fn := range subscribers {
+			fn(next)
+		}
+		s.mu.Lock()
+	}
+	s.dispatching = false
+	completed = true
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *ConfigStore[T]) swap() (ConfigChange[T], uint64, error) {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	ClearSecretFileCache()
+	cfg, err := s.load()
+	if err != nil {
+		return ConfigChange[T]{}, 0, err
+	}
+
+	old := s.current.Swap(cfg)
+	s.swaps++
+	return ConfigChange[T]{Old: *old, New: *cfg}, s.swaps, nil
+}
+
+func (s *ConfigStore[T]) load() (*T, error) {
+	source, err := s.sources()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration sources: %w", err)
+	}
+
+	cfg := new(T)
+	if err := LoadConfigFrom(source, cfg); err != nil {
+		return nil, err
+	}
+
+	if s.validate != nil {
+		if err := s.validate(cfg); err != nil {
+			return nil, fmt.Errorf("invalid configuration: %w", err)
+		}
+	}
+	return cfg, nil
+}
+
+// WatchSignals reloads the store whenever one of the signals (SIGHUP by
+// default) is received. It blocks until ctx is done.
+func (s *ConfigStore[T]) WatchSignals(ctx context.Context, signals ...os.Signal) error {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGHUP}
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, signals...)
+	defer signal.Stop(ch)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ch:
+			_ = s.Reload()
+		}
+	}
+}
+
+// WatchFiles polls the files every interval and reloads the store when any of
+// them changes. It blocks until ctx is done.
+func (s *ConfigStore[T]) WatchFiles(ctx context.Context, interval time.Duration, paths ...string) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := fileStamps(paths)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			current := fileStamps(paths)
+			if current != last {
+				last = current
+				_ = s.Reload()
+			}
+		}
+	}
+}
+
+func fileStamps(paths []string) string {
+	var stamps string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			stamps += path + ":missing;"
+			continue
+		}
+		stamps += fmt.Sprintf("%s:%d:%d;", path, info.ModTime().UnixNano(), info.Size())
+	}
+	return stamps
+}
@@ -0,0 +1,239 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testStoreConfig struct {
+	RateLimit int    `env:"RATE_LIMIT" default:"10"`
+	Feature   bool   `env:"FEATURE"`
+	Recipient string `env:"RECIPIENT" required:"true"`
+}
+
+func newTestStore(t *testing.T, content string) (*ConfigStore[testStoreConfig], string) {
+	t.Helper()
+	path := writeTestFile(t, "config.yaml", content)
+
+	store, err := NewConfigStore(
+		func() (ConfigSource, error) { return NewFileSource(path) },
+		func(cfg *testStoreConfig) error {
+			if cfg.RateLimit <= 0 {
+				return errors.New("rate limit must be positive")
+			}
+			return nil
+		},
+	)
+	require.NoError(t, err)
+	return store, path
+}
+
+func TestConfigStore_Reload(t *testing.T) {
+	store, path := newTestStore(t, "recipient: a@example.com\n")
+	assert.Equal(t, testStoreConfig{RateLimit: 10, Recipient: "a@example.com"}, store.Get())
+
+	var changes []ConfigChange[testStoreConfig]
+	unsubscribe := store.Subscribe(func(change ConfigChange[testStoreConfig]) {
+		changes = append(changes, change)
+	})
+
+	require.NoError(t, os.WriteFile(path, []byte("recipient: b@example.com\nrate_limit: 20\nfeature: true\n"), 0o600))
+	require.NoError(t, store.Reload())
+
+	want := testStoreConfig{RateLimit: 20, Feature: true, Recipient: "b@example.com"}
+	assert.Equal(t, want, store.Get())
+	require.Len(t, changes, 1)
+	assert.Equal(t, "a@example.com", changes[0].Old.Recipient)
+	assert.Equal(t, want, changes[0].New)
+
+	unsubscribe()
+	require.NoError(t, store.Reload())
+	assert.Len(t, changes, 1)
+}
+
+func TestConfigStore_CallbacksMayUseStore(t *testing.T) {
+	store, path := newTestStore(t, "recipient: a@example.com\n")
+
+	calls := 0
+	var unsubscribe func()
+	unsubscribe = store.Subscribe(func(ConfigChange[testStoreConfig]) {
+		calls++
+		unsubscribe()
+		store.Subscribe(func(ConfigChange[testStoreConfig]) {})
+	})
+	store.OnError(func(error) {
+		store.OnError(nil)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert.NoError(t, store.Reload())
+		assert.NoError(t, store.Reload())
+		assert.NoError(t, os.WriteFile(path, []byte("rate_limit: 5\n"), 0o600))
+		assert.Error(t, store.Reload())
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("reload deadlocked")
+	}
+	assert.Equal(t, 1, calls)
+}
+
+func TestConfigStore_ConcurrentReloadsDeliverLatest(t *testing.T) {
+	var loads atomic.Int64
+	store, err := NewConfigStore(
+		func() (ConfigSource, error) {
+			return NewMapSource("test", map[string]string{
+				"RECIPIENT":  "a@example.com",
+				"RATE_LIMIT": strconv.FormatInt(loads.Add(1), 10),
+			}), nil
+		},
+		func(*testStoreConfig) error { return nil },
+	)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var delivered []int
+	store.Subscribe(func(change ConfigChange[testStoreConfig]) {
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		defer mu.Unlock()
+		delivered = append(delivered, change.New.RateLimit)
+	})
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Go(func() {
+			assert.NoError(t, store.Reload())
+		})
+	}
+	wg.Wait()
+
+	require.NotEmpty(t, delivered)
+	assert.True(t, slices.IsSorted(delivered), delivered)
+	assert.Equal(t, store.Get().RateLimit, delivered[len(delivered)-1])
+}
+
+func TestConfigStore_BadReloadKeepsSnapshot(t *testing.T) {
+	store, path := newTestStore(t, "recipient: a@example.com\n")
+
+	var reported []error
+	store.OnError(func(err error) { reported = append(reported, err) })
+	notified := false
+	store.Subscribe(func(ConfigChange[testStoreConfig]) { notified = true })
+
+	for _, content := range []string{
+		"rate_limit: 5\n",
+		"recipient: a@example.com\nrate_limit: -1\n",
+		"recipient: [broken\n",
+	} {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		assert.Error(t, store.Reload())
+	}
+
+	assert.Equal(t, testStoreConfig{RateLimit: 10, Recipient: "a@example.com"}, store.Get())
+	assert.Len(t, reported, 3)
+	assert.False(t, notified)
+}
+
+func TestNewConfigStore_InvalidInitial(t *testing.T) {
+	_, err := NewConfigStore(
+		func() (ConfigSource, error) { return NewMapSource("test", nil), nil },
+		func(*testStoreConfig) error { return nil },
+	)
+	assert.Error(t, err)
+}
+
+func TestConfigStore_WatchFiles(t *testing.T) {
+	store, path := newTestStore(t, "recipient: a@example.com\n")
+
+	changed := make(chan ConfigChange[testStoreConfig], 1)
+	store.Subscribe(func(change ConfigChange[testStoreConfig]) {
+		select {
+		case changed <- change:
+		default:
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = store.WatchFiles(ctx, 10*time.Millisecond, path)
+	}()
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	time.Sleep(30 * time.Millisecond)
+	require.NoError(t, os.WriteFile(path, []byte("recipient: watched@example.com\n"), 0o600))
+
+	select {
+	case change := <-changed:
+		assert.Equal(t, "watched@example.com", change.New.Recipient)
+	case <-time.After(2 * time.Second):
+		t.Fatal("file change was not picked up")
+	}
+}
+
+func TestConfigStore_WatchSignals(t *testing.T) {
+	store, path := newTestStore(t, "recipient: a@example.com\n")
+
+	changed := make(chan ConfigChange[testStoreConfig], 1)
+	store.Subscribe(func(change ConfigChange[testStoreConfig]) {
+		select {
+		case changed <- change:
+		default:
+		}
+	})
+
+	// Keep SIGHUP from terminating the test binary before the watcher subscribes.
+	guard := make(chan os.Signal, 8)
+	signal.Notify(guard, syscall.SIGHUP)
+	defer signal.Stop(guard)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = store.WatchSignals(ctx)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	require.NoError(t, os.WriteFile(path, []byte("recipient: signal@example.com\n"), 0o600))
+	process, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+
+	deadline := time.After(2 * time.Second)
+	for {
+		require.NoError(t, process.Signal(syscall.SIGHUP))
+		select {
+		case change := <-changed:
+			assert.Equal(t, "signal@example.com", change.New.Recipient)
+			return
+		case <-time.After(50 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("signal did not trigger reload")
+		}
+	}
+}
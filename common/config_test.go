@@ -1,10 +1,14 @@
 package common
 
 import (
+	"errors"
+	"math/big"
+	"net/url"
 	"os"
 	"os/exec"
 	"reflect"
 	"testing"
+	"time"
 )
 
 // --- EnvSafe edge cases ---
@@ -167,3 +171,173 @@ func TestEnvMap_InvalidEntry_Fatal(t *testing.T) {
 		t.Fatal("expected subprocess to exit with non-zero due to log.Fatalf, got nil error")
 	}
 }
+
+// --- Typed getters ---
+
+func TestEnvTypedSafe_Success(t *testing.T) {
+	t.Setenv("TEST_ENV_INT64", "9000000000")
+	t.Setenv("TEST_ENV_FLOAT", " 1.5 ")
+	t.Setenv("TEST_ENV_DURATION", "1m30s")
+	t.Setenv("TEST_ENV_URL", "https://example.com:8443/api")
+	t.Setenv("TEST_ENV_RAT", "12.345")
+	t.Setenv("TEST_ENV_TIME", "2025-06-01T10:00:00+02:00")
+
+	i, err := EnvInt64Safe("TEST_ENV_INT64")
+	if err != nil || i != 9000000000 {
+		t.Fatalf("EnvInt64Safe: got %d, %v", i, err)
+	}
+
+	f, err := EnvFloatSafe("TEST_ENV_FLOAT")
+	if err != nil || f != 1.5 {
+		t.Fatalf("EnvFloatSafe: got %v, %v", f, err)
+	}
+
+	d, err := EnvDurationSafe("TEST_ENV_DURATION")
+	if err != nil || d != 90*time.Second {
+		t.Fatalf("EnvDurationSafe: got %v, %v", d, err)
+	}
+
+	u, err := EnvURLSafe("TEST_ENV_URL")
+	if err != nil || u.Host != "example.com:8443" || u.Path != "/api" {
+		t.Fatalf("EnvURLSafe: got %v, %v", u, err)
+	}
+
+	r, err := EnvRatSafe("TEST_ENV_RAT", 2, RoundHalfUp)
+	if err != nil || r.FloatString(2) != "12.35" {
+		t.Fatalf("EnvRatSafe: got %v, %v", r, err)
+	}
+
+	tm, err := EnvTimeSafe("TEST_ENV_TIME")
+	if err != nil || !tm.Equal(time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)) {
+		t.Fatalf("EnvTimeSafe: got %v, %v", tm, err)
+	}
+}
+
+func TestEnvTypedSafe_WrongFormat(t *testing.T) {
+	t.Setenv("TEST_ENV_TYPED_BAD", "bad")
+	t.Setenv("TEST_ENV_URL_NO_SCHEME", "example.com")
+
+	getters := map[string]func() error{
+		"int64":    func() error { _, err := EnvInt64Safe("TEST_ENV_TYPED_BAD"); return err },
+		"float":    func() error { _, err := EnvFloatSafe("TEST_ENV_TYPED_BAD"); return err },
+		"duration": func() error { _, err := EnvDurationSafe("TEST_ENV_TYPED_BAD"); return err },
+		"url":      func() error { _, err := EnvURLSafe("TEST_ENV_URL_NO_SCHEME"); return err },
+		"rat":      func() error { _, err := EnvRatSafe("TEST_ENV_TYPED_BAD", 2, RoundHalfUp); return err },
+		"time":     func() error { _, err := EnvTimeSafe("TEST_ENV_TYPED_BAD"); return err },
+		"size":     func() error { _, err := EnvSizeSafe("TEST_ENV_TYPED_BAD"); return err },
+	}
+	for _, value := range []string{"1/3", "0x10", "0b101", "1_000"} {
+		t.Setenv("TEST_ENV_RAT_BAD", value)
+		if _, err := EnvRatSafe("TEST_ENV_RAT_BAD", 2, RoundHalfUp); err == nil {
+			t.Fatalf("rat %q: expected error, got nil", value)
+		}
+	}
+
+	for name, get := range getters {
+		err := get()
+		if err == nil {
+			t.Fatalf("%s: expected error, got nil", name)
+		}
+		if errors.Is(err, ErrConfigNotSet) {
+			t.Fatalf("%s: format error must not be ErrConfigNotSet", name)
+		}
+	}
+}
+
+func TestEnvSizeSafe(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int64
+	}{
+		{"512", 512},
+		{"10B", 10},
+		{"1k", 1024},
+		{"10MB", 10 << 20},
+		{"1.5GiB", 3 << 29},
+		{"2 TB", 2 << 40},
+	}
+
+	for _, tc := range tests {
+		t.Setenv("TEST_ENV_SIZE", tc.value)
+		got, err := EnvSizeSafe("TEST_ENV_SIZE")
+		if err != nil || got != tc.want {
+			t.Fatalf("EnvSizeSafe(%q): expected %d, got %d, %v", tc.value, tc.want, got, err)
+		}
+	}
+
+	for _, value := range []string{"MB", "10XB", "0.5B"} {
+		t.Setenv("TEST_ENV_SIZE", value)
+		if _, err := EnvSizeSafe("TEST_ENV_SIZE"); err == nil {
+			t.Fatalf("EnvSizeSafe(%q): expected error, got nil", value)
+		}
+	}
+}
+
+func TestEnvOr_Defaults(t *testing.T) {
+	missing := "TEST_ENV_OR_MISSING"
+	defaultURL, _ := url.Parse("http://localhost")
+	defaultRat := big.NewRat(1, 2)
+	defaultTime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if got := EnvOr(missing, "x"); got != "x" {
+		t.Fatalf("EnvOr: got %q", got)
+	}
+	if got := EnvIntOr(missing, 3); got != 3 {
+		t.Fatalf("EnvIntOr: got %d", got)
+	}
+	if got := EnvBoolOr(missing, true); !got {
+		t.Fatalf("EnvBoolOr: got %v", got)
+	}
+	if got := EnvSliceOr(missing, []string{"a"}); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Fatalf("EnvSliceOr: got %v", got)
+	}
+	if got := EnvMapOr(missing, map[string]string{"a": "1"}); !reflect.DeepEqual(got, map[string]string{"a": "1"}) {
+		t.Fatalf("EnvMapOr: got %v", got)
+	}
+	if got := EnvInt64Or(missing, 4); got != 4 {
+		t.Fatalf("EnvInt64Or: got %d", got)
+	}
+	if got := EnvFloatOr(missing, 0.5); got != 0.5 {
+		t.Fatalf("EnvFloatOr: got %v", got)
+	}
+	if got := EnvDurationOr(missing, time.Second); got != time.Second {
+		t.Fatalf("EnvDurationOr: got %v", got)
+	}
+	if got := EnvURLOr(missing, defaultURL); got != defaultURL {
+		t.Fatalf("EnvURLOr: got %v", got)
+	}
+	if got := EnvRatOr(missing, 2, RoundHalfUp, defaultRat); got != defaultRat {
+		t.Fatalf("EnvRatOr: got %v", got)
+	}
+	if got := EnvTimeOr(missing, defaultTime); !got.Equal(defaultTime) {
+		t.Fatalf("EnvTimeOr: got %v", got)
+	}
+	if got := EnvSizeOr(missing, 1024); got != 1024 {
+		t.Fatalf("EnvSizeOr: got %d", got)
+	}
+}
+
+func TestEnvOr_SetValueWins(t *testing.T) {
+	t.Setenv("TEST_ENV_OR_SET", "42")
+
+	if got := EnvIntOr("TEST_ENV_OR_SET", 1); got != 42 {
+		t.Fatalf("EnvIntOr: expected 42, got %d", got)
+	}
+}
+
+func TestEnvIntOr_Invalid_Fatal(t *testing.T) {
+	if os.Getenv("SUBPROC_ENVINTOR_INVALID_FATAL") == "1" {
+		key := "TEST_ENV_INT_OR_INVALID_FATAL"
+		os.Setenv(key, "not-an-int")
+		defer os.Unsetenv(key)
+		_ = EnvIntOr(key, 1)
+		os.Exit(0)
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=TestEnvIntOr_Invalid_Fatal")
+	cmd.Env = append(os.Environ(), "SUBPROC_ENVINTOR_INVALID_FATAL=1")
+	err := cmd.Run()
+	if err == nil {
+		t.Fatal("expected subprocess to exit with non-zero due to log.Fatal, got nil error")
+	}
+}
@@ -0,0 +1,318 @@
+package common
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+var sqlIdentifierRegExp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+type CursorDirection int
+
+const (
+	CursorNext CursorDirection = iota
+	CursorPrev
+)
+
+// KeysetColumn is one column of the keyset sort. The columns must identify a
+// row uniquely (end with the primary key) and must not be NULL.
+type KeysetColumn struct {
+	Column string
+	Desc   bool
+}
+
+// CursorCodec encodes sort key values into opaque cursors signed with
+// HMAC-SHA256, so clients cannot forge or alter them.
+type CursorCodec struct {
+	secret []byte
+}
+
+// MinCursorSecretLength is the shortest secret NewCursorCodec accepts.
+const MinCursorSecretLength = 32
+
+func NewCursorCodec(secret []byte) (*CursorCodec, error) {
+	if len(secret) < MinCursorSecretLength {
+		return nil, fmt.Errorf("cursor secret must be at least %d bytes", MinCursorSecretLength)
+	}
+	return &CursorCodec{secret: slices.Clone(secret)}, nil
+}
+
+type cursorPayload struct {
+	Direction CursorDirection `json:"d"`
+	Keys      [][2]string     `json:"k"`
+}
+
+func (c *CursorCodec) Encode(direction CursorDirection, keys ...any) (string, error) {
+	payload := cursorPayload{Direction: direction, Keys: make([][2]string, len(keys))}
+	for i, key := range keys {
+		encoded, err := encodeCursorKey(key)
+		if err != nil {
+			return "", err
+		}
+		payload.Keys[i] = encoded
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data) + "." + base64.RawURLEncoding.EncodeToString(c.sign(data)), nil
+}
+
+func (c *CursorCodec) Decode(cursor string) (CursorDirection, []any, error) {
+	encodedData, encodedMac, ok := strings.Cut(cursor, ".")
+	if !ok {
+		return 0, nil, ErrInvalidCursor
+	}
+	data, err := base64.RawURLEncoding.DecodeString(encodedData)
+	if err != nil {
+		return 0, nil, ErrInvalidCursor
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(encodedMac)
+	if err != nil || !hmac.Equal(mac, c.sign(data)) {
+		return 0, nil, ErrInvalidCursor
+	}
+
+	var payload cursorPayload
+	if err := json.NewDecoder(bytes.NewReader(data)).Decode(&payload); err != nil {
+		return 0, nil, ErrInvalidCursor
+	}
+	if payload.Direction != CursorNext && payload.Direction != CursorPrev {
+		return 0, nil, ErrInvalidCursor
+	}
+
+	keys := make([]any, len(payload.Keys))
+	for i, encoded := range payload.Keys {
+		key, err := decodeCursorKey(encoded)
+		if err != nil {
+			return 0, nil, ErrInvalidCursor
+		}
+		keys[i] = key
+	}
+	return payload.Direction, keys, nil
+}
+
+func (c *CursorCodec) sign(data []byte) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// Each key is stored as a type tag and its text form. Keys decode to the same
+// Go type, except that int and int32 decode as int64.
+func encodeCursorKey(key any) ([2]string, error) {
+	switch v := key.(type) {
+	case string:
+		return [2]string{"s", v}, nil
+	case int:
+		return [2]string{"i", strconv.FormatInt(int64(v), 10)}, nil
+	case int32:
+		return [2]string{"i", strconv.FormatInt(int64(v), 10)}, nil
+	case int64:
+		return [2]string{"i", strconv.FormatInt(v, 10)}, nil
+	case float64:
+		return [2]string{"f", strconv.FormatFloat(v, 'g', -1, 64)}, nil
+	case bool:
+		return [2]string{"b", strconv.FormatBool(v)}, nil
+	case time.Time:
+		return [2]string{"t", v.Format(time.RFC3339Nano)}, nil
+	case Decimal:
+		return [2]string{"d", v.String()}, nil
+	default:
+		return [2]string{}, fmt.Errorf("unsupported cursor key type %T", key)
+	}
+}
+
+func decodeCursorKey(encoded [2]string) (any, error) {
+	switch encoded[0] {
+	case "s":
+		return encoded[1], nil
+	case "i":
+		return strconv.ParseInt(encoded[1], 10, 64)
+	case "f":
+		return strconv.ParseFloat(encoded[1], 64)
+	case "b":
+		return strconv.ParseBool(encoded[1])
+	case "t":
+		return time.Parse(time.RFC3339Nano, encoded[1])
+	case "d":
+		return ParseDecimal(encoded[1])
+	default:
+		return nil, fmt.Errorf("unknown cursor key type %q", encoded[0])
+	}
+}
+
+type CursorPageable struct {
+	Size      int32
+	Direction CursorDirection
+	Keys      []any
+}
+
+// NewCursorPageable decodes cursor, an empty cursor starts at the first page.
+func NewCursorPageable(size int32, cursor string, codec *CursorCodec) (*CursorPageable, error) {
+	if size <= 0 {
+		size = 20
+	}
+
+	pageable := &CursorPageable{Size: size}
+	if IsBlank(cursor) {
+		return pageable, nil
+	}
+
+	direction, keys, err := codec.Decode(cursor)
+	if err != nil {
+		return nil, err
+	}
+	pageable.Direction = direction
+	pageable.Keys = keys
+	return pageable, nil
+}
+
+func (p *CursorPageable) IsFirst() bool {
+	return len(p.Keys) == 0
+}
+
+// Limit is one more than Size; the extra row tells whether another page exists.
+func (p *CursorPageable) Limit() int64 {
+	return int64(p.Size) + 1
+}
+
+// OrderBy renders the ORDER BY list for the query, reversed when paging back.
+func (p *CursorPageable) OrderBy(columns []KeysetColumn) (string, error) {
+	if err := validateKeysetColumns(columns); err != nil {
+		return "", err
+	}
+
+	parts := make([]string, len(columns))
+	for i, column := range columns {
+		desc := column.Desc != (p.Direction == CursorPrev)
+		if desc {
+			parts[i] = column.Column + " DESC"
+		} else {
+			parts[i] = column.Column + " ASC"
+		}
+	}
+	return strings.Join(parts, ", "), nil
+}
+
+// Where renders the keyset predicate with PostgreSQL placeholders starting at
+// $firstParam, e.g. "(a, b) > ($1, $2)", and returns its arguments. It returns
+// an empty predicate on the first page.
+func (p *CursorPageable) Where(columns []KeysetColumn, firstParam int) (string, []any, error) {
+	if err := validateKeysetColumns(columns); err != nil {
+		return "", nil, err
+	}
+	if p.IsFirst() {
+		return "", nil, nil
+	}
+	if len(p.Keys) != len(columns) {
+		return "", nil, fmt.Errorf("%w: expected %d keys, got %d", ErrInvalidCursor, len(columns), len(p.Keys))
+	}
+
+	op := func(column KeysetColumn) string {
+		if column.Desc != (p.Direction == CursorPrev) {
+			return "<"
+		}
+		return ">"
+	}
+
+	params := make([]string, len(columns))
+	names := make([]string, len(columns))
+	sameDirection := true
+	for i, column := range columns {
+		params[i] = "$" + strconv.Itoa(firstParam+i)
+		names[i] = column.Column
+		sameDirection = sameDirection && column.Desc == columns[0].Desc
+	}
+
+	if len(columns) == 1 {
+		return fmt.Sprintf("%s %s %s", names[0], op(columns[0]), params[0]), slices.Clone(p.Keys), nil
+	}
+	if sameDirection {
+		return fmt.Sprintf("(%s) %s (%s)", strings.Join(names, ", "), op(columns[0]), strings.Join(params, ", ")), slices.Clone(p.Keys), nil
+	}
+
+	// Mixed directions cannot use a row comparison:
+	// a > $1 OR (a = $1 AND b < $2) OR ...
+	terms := make([]string, len(columns))
+	for i, column := range columns {
+		conditions := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			conditions = append(conditions, names[j]+" = "+params[j])
+		}
+		conditions = append(conditions, fmt.Sprintf("%s %s %s", column.Column, op(column), params[i]))
+		terms[i] = "(" + strings.Join(conditions, " AND ") + ")"
+	}
+	return "(" + strings.Join(terms, " OR ") + ")", slices.Clone(p.Keys), nil
+}
+
+func validateKeysetColumns(columns []KeysetColumn) error {
+	if len(columns) == 0 {
+		return errors.New("at least one keyset column is required")
+	}
+	for _, column := range columns {
+		if !sqlIdentifierRegExp.MatchString(column.Column) {
+			return fmt.Errorf("invalid keyset column %q", column.Column)
+		}
+	}
+	return nil
+}
+
+type CursorPage[T any] struct {
+	Size       int32
+	Content    []T
+	NextCursor string
+	PrevCursor string
+	HasNext    bool
+	HasPrev    bool
+	Empty      bool
+}
+
+// NewCursorPage builds a page from rows fetched with pageable's Where, OrderBy
+// and Limit. key returns the keyset column values of a row.
+func NewCursorPage[T any](pageable *CursorPageable, codec *CursorCodec, rows []T, key func(T) []any) (*CursorPage[T], error) {
+	hasMore := len(rows) > int(pageable.Size)
+	content := slices.Clone(rows[:min(len(rows), int(pageable.Size))])
+
+	page := &CursorPage[T]{Size: pageable.Size}
+	if pageable.Direction == CursorPrev {
+		slices.Reverse(content)
+		page.HasPrev = hasMore
+		page.HasNext = true
+	} else {
+		page.HasNext = hasMore
+		page.HasPrev = !pageable.IsFirst()
+	}
+	page.Content = content
+	page.Empty = len(content) == 0
+
+	if page.Empty {
+		page.HasNext, page.HasPrev = false, false
+		return page, nil
+	}
+
+	var err error
+	if page.HasNext {
+		if page.NextCursor, err = codec.Encode(CursorNext, key(content[len(content)-1])...); err != nil {
+			return nil, err
+		}
+	}
+	if page.HasPrev {
+		if page.PrevCursor, err = codec.Encode(CursorPrev, key(content[0])...); err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
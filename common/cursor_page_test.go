@@ -0,0 +1,221 @@
+package common
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type cursorTestRow struct {
+	Name string
+	ID   int64
+}
+
+func newTestCursorCodec(t *testing.T, secret string) *CursorCodec {
+	t.Helper()
+	codec, err := NewCursorCodec([]byte(strings.Repeat(secret, MinCursorSecretLength)))
+	require.NoError(t, err)
+	return codec
+}
+
+func cursorTestKey(row cursorTestRow) []any {
+	return []any{row.Name, row.ID}
+}
+
+// fetchCursorTestRows emulates "WHERE (name, id) > (...) ORDER BY name, id LIMIT n"
+// and its reversed variant for previous pages.
+func fetchCursorTestRows(rows []cursorTestRow, pageable *CursorPageable) []cursorTestRow {
+	compare := func(a cursorTestRow, keys []any) int {
+		return cmp.Or(strings.Compare(a.Name, keys[0].(string)), cmp.Compare(a.ID, keys[1].(int64)))
+	}
+
+	var result []cursorTestRow
+	ordered := slices.Clone(rows)
+	if pageable.Direction == CursorPrev {
+		slices.Reverse(ordered)
+	}
+	for _, row := range ordered {
+		if !pageable.IsFirst() {
+			c := compare(row, pageable.Keys)
+			if pageable.Direction == CursorNext && c <= 0 || pageable.Direction == CursorPrev && c >= 0 {
+				continue
+			}
+		}
+		result = append(result, row)
+		if int64(len(result)) == pageable.Limit() {
+			break
+		}
+	}
+	return result
+}
+
+func TestCursorPage_Traversal(t *testing.T) {
+	codec := newTestCursorCodec(t, "secret")
+	rows := []cursorTestRow{{"a", 1}, {"a", 2}, {"b", 3}, {"c", 4}, {"c", 5}}
+
+	pageable, err := NewCursorPageable(2, "", codec)
+	require.NoError(t, err)
+
+	var forward [][]cursorTestRow
+	var last *CursorPage[cursorTestRow]
+	for {
+		page, err := NewCursorPage(pageable, codec, fetchCursorTestRows(rows, pageable), cursorTestKey)
+		require.NoError(t, err)
+		forward = append(forward, page.Content)
+		last = page
+		if !page.HasNext {
+			break
+		}
+		pageable, err = NewCursorPageable(2, page.NextCursor, codec)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, [][]cursorTestRow{
+		{{"a", 1}, {"a", 2}},
+		{{"b", 3}, {"c", 4}},
+		{{"c", 5}},
+	}, forward)
+	assert.True(t, last.HasPrev)
+	assert.Empty(t, last.NextCursor)
+
+	pageable, err = NewCursorPageable(2, last.PrevCursor, codec)
+	require.NoError(t, err)
+	page, err := NewCursorPage(pageable, codec, fetchCursorTestRows(rows, pageable), cursorTestKey)
+	require.NoError(t, err)
+	assert.Equal(t, []cursorTestRow{{"b", 3}, {"c", 4}}, page.Content)
+	assert.True(t, page.HasPrev)
+	assert.True(t, page.HasNext)
+
+	pageable, err = NewCursorPageable(2, page.PrevCursor, codec)
+	require.NoError(t, err)
+	page, err = NewCursorPage(pageable, codec, fetchCursorTestRows(rows, pageable), cursorTestKey)
+	require.NoError(t, err)
+	assert.Equal(t, []cursorTestRow{{"a", 1}, {"a", 2}}, page.Content)
+	assert.False(t, page.HasPrev)
+	assert.Empty(t, page.PrevCursor)
+}
+
+func TestCursorPage_Empty(t *testing.T) {
+	codec := newTestCursorCodec(t, "secret")
+	pageable, err := NewCursorPageable(0, "", codec)
+	require.NoError(t, err)
+	assert.Equal(t, int32(20), pageable.Size)
+
+	page, err := NewCursorPage(pageable, codec, []cursorTestRow{}, cursorTestKey)
+	require.NoError(t, err)
+	assert.True(t, page.Empty)
+	assert.False(t, page.HasNext)
+	assert.False(t, page.HasPrev)
+}
+
+func TestCursorCodec_RoundTrip(t *testing.T) {
+	codec := newTestCursorCodec(t, "secret")
+	created := time.Date(2025, 3, 4, 5, 6, 7, 89, time.UTC)
+
+	cursor, err := codec.Encode(CursorPrev, "name", 42, int64(7), 1.5, true, created, MustParseDecimal("12.30"))
+	require.NoError(t, err)
+
+	direction, keys, err := codec.Decode(cursor)
+	require.NoError(t, err)
+	assert.Equal(t, CursorPrev, direction)
+	require.Len(t, keys, 7)
+	assert.Equal(t, "name", keys[0])
+	assert.Equal(t, int64(42), keys[1])
+	assert.Equal(t, int64(7), keys[2])
+	assert.Equal(t, 1.5, keys[3])
+	assert.Equal(t, true, keys[4])
+	assert.True(t, created.Equal(keys[5].(time.Time)))
+	assert.Equal(t, "12.30", keys[6].(Decimal).String())
+
+	_, err = codec.Encode(CursorNext, struct{}{})
+	assert.Error(t, err)
+}
+
+func TestNewCursorCodec_ShortSecret(t *testing.T) {
+	for _, secret := range [][]byte{nil, {}, []byte("secret"), make([]byte, MinCursorSecretLength-1)} {
+		_, err := NewCursorCodec(secret)
+		assert.Error(t, err)
+	}
+}
+
+func TestCursorCodec_TamperEvident(t *testing.T) {
+	codec := newTestCursorCodec(t, "secret")
+	cursor, err := codec.Encode(CursorNext, "a", int64(1))
+	require.NoError(t, err)
+
+	data, mac, _ := strings.Cut(cursor, ".")
+	forged, err := newTestCursorCodec(t, "other").Encode(CursorNext, "z", int64(9))
+	require.NoError(t, err)
+	forgedData, _, _ := strings.Cut(forged, ".")
+
+	for _, bad := range []string{
+		"",
+		"garbage",
+		data,
+		forgedData + "." + mac,
+		forged,
+		data + "." + mac + "x",
+	} {
+		_, _, err := codec.Decode(bad)
+		assert.ErrorIs(t, err, ErrInvalidCursor, bad)
+	}
+
+	_, err = NewCursorPageable(10, forged, codec)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestCursorPageable_Where(t *testing.T) {
+	asc := []KeysetColumn{{Column: "name"}, {Column: "id"}}
+	mixed := []KeysetColumn{{Column: "created_at", Desc: true}, {Column: "t.id"}}
+
+	first := &CursorPageable{Size: 10}
+	where, args, err := first.Where(asc, 1)
+	require.NoError(t, err)
+	assert.Empty(t, where)
+	assert.Empty(t, args)
+
+	next := &CursorPageable{Size: 10, Direction: CursorNext, Keys: []any{"b", int64(3)}}
+	where, args, err = next.Where(asc, 3)
+	require.NoError(t, err)
+	assert.Equal(t, "(name, id) > ($3, $4)", where)
+	assert.Equal(t, []any{"b", int64(3)}, args)
+
+	prev := &CursorPageable{Size: 10, Direction: CursorPrev, Keys: []any{"b", int64(3)}}
+	where, _, err = prev.Where(asc, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "(name, id) < ($1, $2)", where)
+
+	where, _, err = next.Where(mixed, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "((created_at < $1) OR (created_at = $1 AND t.id > $2))", where)
+
+	single := &CursorPageable{Size: 10, Direction: CursorNext, Keys: []any{int64(3)}}
+	where, _, err = single.Where([]KeysetColumn{{Column: "id", Desc: true}}, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "id < $1", where)
+
+	_, _, err = single.Where(asc, 1)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+	_, _, err = next.Where([]KeysetColumn{{Column: "name; DROP TABLE x"}, {Column: "id"}}, 1)
+	assert.Error(t, err)
+}
+
+func TestCursorPageable_OrderBy(t *testing.T) {
+	columns := []KeysetColumn{{Column: "created_at", Desc: true}, {Column: "id"}}
+
+	orderBy, err := (&CursorPageable{}).OrderBy(columns)
+	require.NoError(t, err)
+	assert.Equal(t, "created_at DESC, id ASC", orderBy)
+
+	orderBy, err = (&CursorPageable{Direction: CursorPrev}).OrderBy(columns)
+	require.NoError(t, err)
+	assert.Equal(t, "created_at ASC, id DESC", orderBy)
+
+	_, err = (&CursorPageable{}).OrderBy(nil)
+	assert.Error(t, err)
+}
@@ -1,18 +1,51 @@
 package common
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 	"strconv"
 )
 
+// RoundingMode follows the semantics of Java's java.math.RoundingMode.
 type RoundingMode int
 
 const (
 	RoundHalfUp RoundingMode = iota
 	RoundUp
+	RoundDown
+	RoundCeiling
+	RoundFloor
+	RoundHalfDown
+	RoundHalfEven
+	RoundUnnecessary
 )
 
+var ErrRoundingNecessary = errors.New("rounding necessary")
+
+func (m RoundingMode) String() string {
+	switch m {
+	case RoundHalfUp:
+		return "HALF_UP"
+	case RoundUp:
+		return "UP"
+	case RoundDown:
+		return "DOWN"
+	case RoundCeiling:
+		return "CEILING"
+	case RoundFloor:
+		return "FLOOR"
+	case RoundHalfDown:
+		return "HALF_DOWN"
+	case RoundHalfEven:
+		return "HALF_EVEN"
+	case RoundUnnecessary:
+		return "UNNECESSARY"
+	default:
+		return fmt.Sprintf("RoundingMode(%d)", int(m))
+	}
+}
+
 func ToRat(f float64, scale int, mode RoundingMode) (*big.Rat, error) {
 	if scale < 0 {
 		return nil, fmt.Errorf("scale must be >= 0, got %d", scale)
@@ -23,78 +56,85 @@ func ToRat(f float64, scale int, mode RoundingMode) (*big.Rat, error) {
 	if _, ok := r.SetString(s); !ok {
 		return nil, fmt.Errorf("cannot parse float64 as decimal string: %q", s)
 	}
-	return Rescale(r, scale, mode), nil
+	return RescaleSafe(r, scale, mode)
 }
 
+// Rescale is like RescaleSafe but panics on an invalid scale or mode, or when
+// RoundUnnecessary would lose precision.
 func Rescale(value *big.Rat, scale int, mode RoundingMode) *big.Rat {
-	if value == nil {
-		return nil
-	}
-	if scale < 0 {
-		panic("scale must be >= 0")
-	}
-
-	switch mode {
-	case RoundUp:
-		return roundUp(value, scale)
-	case RoundHalfUp:
-		return roundHalfUp(value, scale)
-	default:
-		panic("unsupported rounding mode")
+	r, err := RescaleSafe(value, scale, mode)
+	if err != nil {
+		panic(err.Error())
 	}
+	return r
 }
 
-func roundHalfUp(r *big.Rat, scale int) *big.Rat {
-	// factor = 10^scale
-	factorInt := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil)
-	factor := new(big.Rat).SetInt(factorInt)
-
-	// x = r * factor
-	x := new(big.Rat).Mul(r, factor)
+func RescaleSafe(value *big.Rat, scale int, mode RoundingMode) (*big.Rat, error) {
+	if value == nil {
+		return nil, nil
+	}
 
-	// q = floor(x) toward zero (Quo gives truncated toward zero)
-	q := new(big.Int).Quo(x.Num(), x.Denom())
+	q, err := roundToScale(value, scale, mode)
+	if err != nil {
+		return nil, err
+	}
 
-	// frac = x - q
-	frac := new(big.Rat).Sub(x, new(big.Rat).SetInt(q))
+	// result = q / 10^scale
+	return new(big.Rat).SetFrac(q, pow10(scale)), nil
+}
 
-	// For negatives, truncation toward zero means frac is negative or zero.
-	// We want HALF_UP "away from zero" when |frac| >= 0.5.
-	absFrac := new(big.Rat).Abs(frac)
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
 
-	if absFrac.Cmp(big.NewRat(1, 2)) >= 0 {
-		if x.Sign() >= 0 {
-			q.Add(q, big.NewInt(1))
-		} else {
-			q.Sub(q, big.NewInt(1))
-		}
+// roundToScale returns r * 10^scale rounded to an integer with mode.
+func roundToScale(r *big.Rat, scale int, mode RoundingMode) (*big.Int, error) {
+	if scale < 0 {
+		return nil, fmt.Errorf("scale must be >= 0, got %d", scale)
+	}
+	if mode < RoundHalfUp || mode > RoundUnnecessary {
+		return nil, fmt.Errorf("unsupported rounding mode %s", mode)
 	}
 
-	// result = q / factor
-	return new(big.Rat).SetInt(q).Quo(new(big.Rat).SetInt(q), factor)
-}
+	// x = r * 10^scale
+	x := new(big.Rat).Mul(r, new(big.Rat).SetInt(pow10(scale)))
 
-func roundUp(r *big.Rat, scale int) *big.Rat {
-	// factor = 10^scale
-	factorInt := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil)
-	factor := new(big.Rat).SetInt(factorInt)
+	// q is truncated toward zero, rem carries the sign of x.
+	q, rem := new(big.Int).QuoRem(x.Num(), x.Denom(), new(big.Int))
+	if rem.Sign() == 0 {
+		return q, nil
+	}
 
-	// x = r * factor
-	x := new(big.Rat).Mul(r, factor)
+	// half compares the discarded fraction with 0.5.
+	half := new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2)).Cmp(x.Denom())
+	positive := x.Sign() > 0
 
-	// q = trunc toward zero
-	q := new(big.Int).Quo(x.Num(), x.Denom())
+	var away bool
+	switch mode {
+	case RoundUp:
+		away = true
+	case RoundDown:
+		away = false
+	case RoundCeiling:
+		away = positive
+	case RoundFloor:
+		away = !positive
+	case RoundHalfUp:
+		away = half >= 0
+	case RoundHalfDown:
+		away = half > 0
+	case RoundHalfEven:
+		away = half > 0 || half == 0 && q.Bit(0) == 1
+	case RoundUnnecessary:
+		return nil, fmt.Errorf("%w: %s does not fit scale %d", ErrRoundingNecessary, r.RatString(), scale)
+	}
 
-	// if there is any fractional part → move away from zero
-	rem := new(big.Int).Rem(x.Num(), x.Denom())
-	if rem.Sign() != 0 {
-		if x.Sign() >= 0 {
+	if away {
+		if positive {
 			q.Add(q, big.NewInt(1))
 		} else {
 			q.Sub(q, big.NewInt(1))
 		}
 	}
-
-	// result = q / factor
-	return new(big.Rat).Quo(new(big.Rat).SetInt(q), factor)
+	return q, nil
 }
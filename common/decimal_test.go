@@ -1,6 +1,7 @@
 package common
 
 import (
+	"errors"
 	"math/big"
 	"testing"
 )
@@ -137,3 +138,95 @@ func TestToRat_RescalesFloatInput(t *testing.T) {
 	}
 	assertRatEq(t, got, "12.35")
 }
+
+// Table from the java.math.RoundingMode documentation.
+func TestRescaleSafe_AllModes(t *testing.T) {
+	inputs := []string{"5.5", "2.5", "1.6", "1.1", "1.0", "-1.0", "-1.1", "-1.6", "-2.5", "-5.5"}
+	tests := []struct {
+		mode RoundingMode
+		want []string
+	}{
+		{RoundUp, []string{"6", "3", "2", "2", "1", "-1", "-2", "-2", "-3", "-6"}},
+		{RoundDown, []string{"5", "2", "1", "1", "1", "-1", "-1", "-1", "-2", "-5"}},
+		{RoundCeiling, []string{"6", "3", "2", "2", "1", "-1", "-1", "-1", "-2", "-5"}},
+		{RoundFloor, []string{"5", "2", "1", "1", "1", "-1", "-2", "-2", "-3", "-6"}},
+		{RoundHalfUp, []string{"6", "3", "2", "1", "1", "-1", "-1", "-2", "-3", "-6"}},
+		{RoundHalfDown, []string{"5", "2", "2", "1", "1", "-1", "-1", "-2", "-2", "-5"}},
+		{RoundHalfEven, []string{"6", "2", "2", "1", "1", "-1", "-1", "-2", "-2", "-6"}},
+	}
+
+	for _, tc := range tests {
+		for i, in := range inputs {
+			got, err := RescaleSafe(mustRat(t, in), 0, tc.mode)
+			if err != nil {
+				t.Fatalf("%s(%s): unexpected error %v", tc.mode, in, err)
+			}
+			if got.Cmp(mustRat(t, tc.want[i])) != 0 {
+				t.Fatalf("%s(%s): got %s want %s", tc.mode, in, got.RatString(), tc.want[i])
+			}
+		}
+	}
+}
+
+func TestRescaleSafe_RoundHalfEven_Scale(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"2.345", "2.34"},
+		{"2.355", "2.36"},
+		{"2.3451", "2.35"},
+		{"-2.345", "-2.34"},
+	}
+
+	for _, tc := range tests {
+		got, err := RescaleSafe(mustRat(t, tc.in), 2, RoundHalfEven)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		assertRatEq(t, got, tc.want)
+	}
+}
+
+func TestRescaleSafe_RoundUnnecessary(t *testing.T) {
+	got, err := RescaleSafe(mustRat(t, "1.50"), 1, RoundUnnecessary)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	assertRatEq(t, got, "1.5")
+
+	_, err = RescaleSafe(mustRat(t, "1.55"), 1, RoundUnnecessary)
+	if !errors.Is(err, ErrRoundingNecessary) {
+		t.Fatalf("expected ErrRoundingNecessary, got %v", err)
+	}
+}
+
+func TestRescaleSafe_InvalidArguments(t *testing.T) {
+	if _, err := RescaleSafe(mustRat(t, "1"), -1, RoundHalfUp); err == nil {
+		t.Fatalf("expected error for negative scale, got nil")
+	}
+	if _, err := RescaleSafe(mustRat(t, "1"), 2, RoundingMode(99)); err == nil {
+		t.Fatalf("expected error for unsupported mode, got nil")
+	}
+	if got, err := RescaleSafe(nil, 2, RoundHalfUp); got != nil || err != nil {
+		t.Fatalf("expected nil, nil for nil input, got %v, %v", got, err)
+	}
+}
+
+func TestRescale_PanicsOnUnsupportedMode(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("expected panic for unsupported mode, got none")
+		}
+	}()
+	_ = Rescale(mustRat(t, "123.1"), 0, RoundingMode(99))
+}
+
+func TestRoundingMode_String(t *testing.T) {
+	if RoundHalfEven.String() != "HALF_EVEN" {
+		t.Fatalf("got %s", RoundHalfEven)
+	}
+	if RoundingMode(99).String() != "RoundingMode(99)" {
+		t.Fatalf("got %s", RoundingMode(99))
+	}
+}
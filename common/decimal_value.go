@@ -0,0 +1,322 @@
+package common
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var ErrDivisionByZero = errors.New("division by zero")
+
+// Decimal is an immutable decimal number represented as unscaled / 10^scale,
+// like Java's BigDecimal. The zero value is 0 with scale 0.
+type Decimal struct {
+	unscaled *big.Int
+	scale    int
+}
+
+func NewDecimal(unscaled int64, scale int) Decimal {
+	return NewDecimalFromBigInt(big.NewInt(unscaled), scale)
+}
+
+func NewDecimalFromBigInt(unscaled *big.Int, scale int) Decimal {
+	if scale < 0 {
+		panic("scale must be >= 0")
+	}
+	if unscaled == nil {
+		return Decimal{scale: scale}
+	}
+	return Decimal{unscaled: new(big.Int).Set(unscaled), scale: scale}
+}
+
+func DecimalFromRat(r *big.Rat, scale int, mode RoundingMode) (Decimal, error) {
+	if r == nil {
+		return Decimal{}, errors.New("nil rational")
+	}
+	unscaled, err := roundToScale(r, scale, mode)
+	if err != nil {
+		return Decimal{}, err
+	}
+	return Decimal{unscaled: unscaled, scale: scale}, nil
+}
+
+func DecimalFromFloat(f float64, scale int, mode RoundingMode) (Decimal, error) {
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Decimal{}, fmt.Errorf("cannot parse float64 as decimal string: %q", s)
+	}
+	return DecimalFromRat(r, scale, mode)
+}
+
+// maxParsedScale bounds the exponent and scale accepted by ParseDecimal, so
+// input like "1e100000000" cannot make it build a huge number.
+const maxParsedScale = 10000
+
+// ParseDecimal parses plain ("-12.30") and exponent ("1.5E-3") notation. The
+// scale is the number of fraction digits written, so "12.30" has scale 2.
+// Exponents and scales beyond ±10000 are rejected.
+func ParseDecimal(s string) (Decimal, error) {
+	original := s
+	s = strings.TrimSpace(s)
+
+	exponent := 0
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.Atoi(s[i+1:])
+		if err != nil || e > maxParsedScale || e < -maxParsedScale {
+			return Decimal{}, fmt.Errorf("invalid decimal %q", original)
+		}
+		exponent = e
+		s = s[:i]
+	}
+
+	sign := ""
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		if s[0] == '-' {
+			sign = "-"
+		}
+		s = s[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if intPart == "" && fracPart == "" || !isDigits(intPart) || !isDigits(fracPart) {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", original)
+	}
+
+	unscaled, ok := new(big.Int).SetString(sign+intPart+fracPart, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", original)
+	}
+
+	scale := len(fracPart) - exponent
+	if scale > maxParsedScale || scale < -maxParsedScale {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", original)
+	}
+	if scale < 0 {
+		unscaled.Mul(unscaled, pow10(-scale))
+		scale = 0
+	}
+	return Decimal{unscaled: unscaled, scale: scale}, nil
+}
+
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (d Decimal) value() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+	return d.unscaled
+}
+
+func (d Decimal) Unscaled() *big.Int {
+	return new(big.Int).Set(d.value())
+}
+
+func (d Decimal) Scale() int {
+	return d.scale
+}
+
+func (d Decimal) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(d.value(), pow10(d.scale))
+}
+
+func (d Decimal) Sign() int {
+	return d.value().Sign()
+}
+
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+func (d Decimal) Neg() Decimal {
+	return Decimal{unscaled: new(big.Int).Neg(d.value()), scale: d.scale}
+}
+
+func (d Decimal) Abs() Decimal {
+	return Decimal{unscaled: new(big.Int).Abs(d.value()), scale: d.scale}
+}
+
+// withScale returns the unscaled value of d expressed at a scale >= d.scale.
+func (d Decimal) withScale(scale int) *big.Int {
+	if scale == d.scale {
+		return d.value()
+	}
+	return new(big.Int).Mul(d.value(), pow10(scale-d.scale))
+}
+
+// Add returns d + o exactly, at the larger of both scales.
+func (d Decimal) Add(o Decimal) Decimal {
+	scale := max(d.scale, o.scale)
+	return Decimal{unscaled: new(big.Int).Add(d.withScale(scale), o.withScale(scale)), scale: scale}
+}
+
+// Sub returns d - o exactly, at the larger of both scales.
+func (d Decimal) Sub(o Decimal) Decimal {
+	scale := max(d.scale, o.scale)
+	return Decimal{unscaled: new(big.Int).Sub(d.withScale(scale), o.withScale(scale)), scale: scale}
+}
+
+// Mul returns d * o exactly, at the sum of both scales.
+func (d Decimal) Mul(o Decimal) Decimal {
+	return Decimal{unscaled: new(big.Int).Mul(d.value(), o.value()), scale: d.scale + o.scale}
+}
+
+func (d Decimal) MulRound(o Decimal, scale int, mode RoundingMode) (Decimal, error) {
+	return d.Mul(o).Rescale(scale, mode)
+}
+
+func (d Decimal) Div(o Decimal, scale int, mode RoundingMode) (Decimal, error) {
+	if o.IsZero() {
+		return Decimal{}, ErrDivisionByZero
+	}
+	return DecimalFromRat(new(big.Rat).Quo(d.Rat(), o.Rat()), scale, mode)
+}
+
+func (d Decimal) Rescale(scale int, mode RoundingMode) (Decimal, error) {
+	if scale >= d.scale {
+		return Decimal{unscaled: new(big.Int).Set(d.withScale(scale)), scale: scale}, nil
+	}
+	return DecimalFromRat(d.Rat(), scale, mode)
+}
+
+func (d Decimal) Cmp(o Decimal) int {
+	scale := max(d.scale, o.scale)
+	return d.withScale(scale).Cmp(o.withScale(scale))
+}
+
+// Equal compares numeric values, so 1.0 equals 1.00.
+func (d Decimal) Equal(o Decimal) bool {
+	return d.Cmp(o) == 0
+}
+
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.value()).String()
+	if d.scale > 0 {
+		if len(digits) <= d.scale {
+			digits = strings.Repeat("0", d.scale-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-d.scale] + "." + digits[len(digits)-d.scale:]
+	}
+	if d.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON accepts both JSON numbers and strings.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		data = []byte(s)
+	}
+	return d.UnmarshalText(data)
+}
+
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Decimal) UnmarshalText(text []byte) error {
+	parsed, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+func (d *Decimal) Scan(src any) error {
+	switch v := src.(type) {
+	case string:
+		return d.UnmarshalText([]byte(v))
+	case []byte:
+		return d.UnmarshalText(v)
+	case int64:
+		*d = NewDecimal(v, 0)
+		return nil
+	case float64:
+		return d.UnmarshalText([]byte(strconv.FormatFloat(v, 'f', -1, 64)))
+	case nil:
+		return errors.New("cannot scan NULL into Decimal, use NullDecimal")
+	default:
+		return fmt.Errorf("cannot scan %T into Decimal", src)
+	}
+}
+
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+type NullDecimal struct {
+	Decimal Decimal
+	Valid   bool
+}
+
+func (n *NullDecimal) Scan(src any) error {
+	if src == nil {
+		n.Decimal, n.Valid = Decimal{}, false
+		return nil
+	}
+	if err := n.Decimal.Scan(src); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+func (n NullDecimal) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Decimal.Value()
+}
+
+func (n NullDecimal) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return n.Decimal.MarshalJSON()
+}
+
+func (n *NullDecimal) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		n.Decimal, n.Valid = Decimal{}, false
+		return nil
+	}
+	if err := n.Decimal.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
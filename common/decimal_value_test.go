@@ -0,0 +1,211 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		in    string
+		want  string
+		scale int
+	}{
+		{"0", "0", 0},
+		{"12.30", "12.30", 2},
+		{"-0.05", "-0.05", 2},
+		{"+7", "7", 0},
+		{".5", "0.5", 1},
+		{"5.", "5", 0},
+		{"1.5E-3", "0.0015", 4},
+		{"1.5e2", "150", 0},
+		{" 42.000 ", "42.000", 3},
+		{"1e-10000", "0." + strings.Repeat("0", 9999) + "1", 10000},
+	}
+
+	for _, tc := range tests {
+		d, err := ParseDecimal(tc.in)
+		require.NoError(t, err, tc.in)
+		assert.Equal(t, tc.want, d.String(), tc.in)
+		assert.Equal(t, tc.scale, d.Scale(), tc.in)
+	}
+
+	for _, in := range []string{"", "-", ".", "1.2.3", "abc", "1e", "1,5", "--1"} {
+		_, err := ParseDecimal(in)
+		assert.Error(t, err, in)
+	}
+
+	for _, in := range []string{"1e100000000", "1e-2147483648", "1e10001", "0.1e-10000", "1e99999999999999999999"} {
+		_, err := ParseDecimal(in)
+		assert.ErrorContains(t, err, "invalid decimal", in)
+	}
+
+	var d Decimal
+	assert.Error(t, json.Unmarshal([]byte(`"1e100000000"`), &d))
+}
+
+func TestDecimal_ZeroValue(t *testing.T) {
+	var d Decimal
+	assert.Equal(t, "0", d.String())
+	assert.True(t, d.IsZero())
+	assert.Equal(t, "1.5", d.Add(MustParseDecimal("1.5")).String())
+}
+
+func TestDecimal_Arithmetic(t *testing.T) {
+	a := MustParseDecimal("10.25")
+	b := MustParseDecimal("3.1")
+
+	assert.Equal(t, "13.35", a.Add(b).String())
+	assert.Equal(t, "7.15", a.Sub(b).String())
+	assert.Equal(t, "-7.15", b.Sub(a).String())
+	assert.Equal(t, "31.775", a.Mul(b).String())
+	assert.Equal(t, "-10.25", a.Neg().String())
+	assert.Equal(t, "10.25", a.Neg().Abs().String())
+
+	m, err := a.MulRound(b, 2, RoundHalfUp)
+	require.NoError(t, err)
+	assert.Equal(t, "31.78", m.String())
+
+	q, err := a.Div(b, 4, RoundHalfUp)
+	require.NoError(t, err)
+	assert.Equal(t, "3.3065", q.String())
+
+	q, err = NewDecimal(1, 0).Div(NewDecimal(3, 0), 2, RoundUp)
+	require.NoError(t, err)
+	assert.Equal(t, "0.34", q.String())
+
+	_, err = a.Div(Decimal{}, 2, RoundHalfUp)
+	assert.True(t, errors.Is(err, ErrDivisionByZero))
+}
+
+func TestDecimal_Rescale(t *testing.T) {
+	d := MustParseDecimal("-1.005")
+
+	up, err := d.Rescale(5, RoundHalfUp)
+	require.NoError(t, err)
+	assert.Equal(t, "-1.00500", up.String())
+
+	down, err := d.Rescale(2, RoundHalfUp)
+	require.NoError(t, err)
+	assert.Equal(t, "-1.01", down.String())
+
+	_, err = d.Rescale(-1, RoundHalfUp)
+	assert.Error(t, err)
+}
+
+func TestDecimal_Compare(t *testing.T) {
+	assert.True(t, MustParseDecimal("1.0").Equal(MustParseDecimal("1.00")))
+	assert.Equal(t, -1, MustParseDecimal("1.09").Cmp(MustParseDecimal("1.1")))
+	assert.Equal(t, 1, MustParseDecimal("0").Cmp(MustParseDecimal("-0.001")))
+	assert.Equal(t, -1, MustParseDecimal("-2").Sign())
+}
+
+func TestDecimal_FromRatAndFloat(t *testing.T) {
+	d, err := DecimalFromRat(big.NewRat(2, 3), 3, RoundHalfUp)
+	require.NoError(t, err)
+	assert.Equal(t, "0.667", d.String())
+	assert.Equal(t, big.NewRat(667, 1000), d.Rat())
+
+	d, err = DecimalFromFloat(12.345, 2, RoundHalfUp)
+	require.NoError(t, err)
+	assert.Equal(t, "12.35", d.String())
+
+	_, err = DecimalFromRat(nil, 2, RoundHalfUp)
+	assert.Error(t, err)
+}
+
+func TestDecimal_Json(t *testing.T) {
+	type invoice struct {
+		Total  Decimal     `json:"total"`
+		Tax    NullDecimal `json:"tax"`
+		Refund NullDecimal `json:"refund"`
+	}
+
+	in := invoice{Total: MustParseDecimal("1234.50"), Tax: NullDecimal{Decimal: MustParseDecimal("0.20"), Valid: true}}
+	data, err := json.Marshal(in)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"total":1234.50,"tax":0.20,"refund":null}`, string(data))
+	assert.Contains(t, string(data), "1234.50")
+
+	var out invoice
+	require.NoError(t, json.Unmarshal([]byte(`{"total":"99.90","tax":0.2,"refund":null}`), &out))
+	assert.Equal(t, "99.90", out.Total.String())
+	assert.True(t, out.Tax.Valid)
+	assert.Equal(t, "0.2", out.Tax.Decimal.String())
+	assert.False(t, out.Refund.Valid)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"total":"abc"}`), &out))
+}
+
+func TestDecimal_Text(t *testing.T) {
+	var d Decimal
+	require.NoError(t, d.UnmarshalText([]byte("3.14")))
+	text, err := d.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "3.14", string(text))
+}
+
+func TestDecimal_Sql(t *testing.T) {
+	var d Decimal
+	require.NoError(t, d.Scan("12.340"))
+	assert.Equal(t, "12.340", d.String())
+	require.NoError(t, d.Scan([]byte("-1.5")))
+	assert.Equal(t, "-1.5", d.String())
+	require.NoError(t, d.Scan(int64(7)))
+	assert.Equal(t, "7", d.String())
+	require.NoError(t, d.Scan(2.5))
+	assert.Equal(t, "2.5", d.String())
+	assert.Error(t, d.Scan(nil))
+	assert.Error(t, d.Scan(true))
+
+	v, err := MustParseDecimal("10.00").Value()
+	require.NoError(t, err)
+	assert.Equal(t, "10.00", v)
+
+	var n NullDecimal
+	require.NoError(t, n.Scan(nil))
+	assert.False(t, n.Valid)
+	v, err = n.Value()
+	require.NoError(t, err)
+	assert.Nil(t, v)
+
+	require.NoError(t, n.Scan("1.1"))
+	assert.True(t, n.Valid)
+	v, err = n.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "1.1", v)
+}
+
+func TestDecimal_Immutable(t *testing.T) {
+	unscaled := big.NewInt(100)
+	d := NewDecimalFromBigInt(unscaled, 2)
+	unscaled.SetInt64(5)
+	d.Unscaled().SetInt64(9)
+	_ = d.Add(NewDecimal(1, 0))
+	assert.Equal(t, "1.00", d.String())
+}
+
+func TestDecimal_RoundingModes(t *testing.T) {
+	d := MustParseDecimal("-2.345")
+
+	even, err := d.Rescale(2, RoundHalfEven)
+	require.NoError(t, err)
+	assert.Equal(t, "-2.34", even.String())
+
+	floor, err := d.Rescale(2, RoundFloor)
+	require.NoError(t, err)
+	assert.Equal(t, "-2.35", floor.String())
+
+	_, err = d.Rescale(2, RoundUnnecessary)
+	assert.ErrorIs(t, err, ErrRoundingNecessary)
+
+	q, err := NewDecimal(10, 0).Div(NewDecimal(4, 0), 1, RoundUnnecessary)
+	require.NoError(t, err)
+	assert.Equal(t, "2.5", q.String())
+}
@@ -0,0 +1,213 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/idna"
+)
+
+var ErrInvalidEmail = errors.New("invalid email address")
+
+type EmailStrictness int
+
+const (
+	// EmailLenient accepts any RFC 5322 address, including single label
+	// domains and domain literals like [192.0.2.1].
+	EmailLenient EmailStrictness = iota
+	// EmailStandard additionally requires a domain with a top-level domain of
+	// at least two characters, the usual rule for public addresses.
+	EmailStandard
+	// EmailStrict additionally requires an ASCII dot-atom local part, i.e. no
+	// quoting and no UTF-8.
+	EmailStrict
+)
+
+// EmailPolicy configures ParseEmail. The checks are offline, nothing is
+// resolved in DNS.
+type EmailPolicy struct {
+	Strictness          EmailStrictness
+	EnforceLengthLimits bool
+	RejectDisposable    bool
+}
+
+var DefaultEmailPolicy = EmailPolicy{Strictness: EmailStandard, EnforceLengthLimits: true}
+
+// EmailAddress is a parsed address. Domain is lower case ASCII (punycode for
+// IDN domains), UnicodeDomain its Unicode form.
+type EmailAddress struct {
+	DisplayName   string
+	LocalPart     string
+	Domain        string
+	UnicodeDomain string
+}
+
+// Address returns the addr-spec with the ASCII domain, quoting the local part
+// when needed.
+func (a *EmailAddress) Address() string {
+	return quoteEmailLocalPart(a.LocalPart) + "@" + a.Domain
+}
+
+func (a *EmailAddress) String() string {
+	return (&mail.Address{Name: a.DisplayName, Address: a.LocalPart + "@" + a.Domain}).String()
+}
+
+var disposableEmailDomains = struct {
+	sync.RWMutex
+	domains map[string]struct{}
+}{domains: map[string]struct{}{
+	"10minutemail.com":  {},
+	"dispostable.com":   {},
+	"getnada.com":       {},
+	"guerrillamail.com": {},
+	"mailinator.com":    {},
+	"maildrop.cc":       {},
+	"sharklasers.com":   {},
+	"temp-mail.org":     {},
+	"tempmail.com":      {},
+	"throwawaymail.com": {},
+	"trashmail.com":     {},
+	"yopmail.com":       {},
+}}
+
+// RegisterDisposableEmailDomains extends the built-in list of throwaway mail
+// providers; subdomains of a listed domain are disposable too.
+func RegisterDisposableEmailDomains(domains ...string) {
+	disposableEmailDomains.Lock()
+	defer disposableEmailDomains.Unlock()
+	for _, domain := range domains {
+		if ascii, err := idna.Lookup.ToASCII(strings.TrimSpace(domain)); err == nil && ascii != "" {
+			disposableEmailDomains.domains[strings.ToLower(ascii)] = struct{}{}
+		}
+	}
+}
+
+func IsDisposableEmailDomain(domain string) bool {
+	disposableEmailDomains.RLock()
+	defer disposableEmailDomains.RUnlock()
+	for domain != "" {
+		if _, ok := disposableEmailDomains.domains[domain]; ok {
+			return true
+		}
+		_, domain, _ = strings.Cut(domain, ".")
+	}
+	return false
+}
+
+// ParseEmail parses "john@example.com" or "John Doe <john@example.com>" with
+// DefaultEmailPolicy.
+func ParseEmail(s string) (*EmailAddress, error) {
+	return DefaultEmailPolicy.Parse(s)
+}
+
+func (p EmailPolicy) Parse(s string) (*EmailAddress, error) {
+	parsed, err := mail.ParseAddress(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("%w %q", ErrInvalidEmail, s)
+	}
+
+	at := strings.LastIndex(parsed.Address, "@")
+	address := &EmailAddress{DisplayName: parsed.Name, LocalPart: parsed.Address[:at]}
+	if err := p.parseDomain(address, parsed.Address[at+1:]); err != nil {
+		return nil, fmt.Errorf("%w %q: %v", ErrInvalidEmail, s, err)
+	}
+
+	if p.Strictness >= EmailStrict && !isEmailDotAtom(address.LocalPart) {
+		return nil, fmt.Errorf("%w %q: local part must be an unquoted ASCII dot-atom", ErrInvalidEmail, s)
+	}
+	if p.EnforceLengthLimits {
+		// RFC 5321: 64 octets for the local part, 254 for the whole path.
+		if len(address.LocalPart) > 64 {
+			return nil, fmt.Errorf("%w %q: local part longer than 64 octets", ErrInvalidEmail, s)
+		}
+		if len(address.Address()) > 254 {
+			return nil, fmt.Errorf("%w %q: address longer than 254 octets", ErrInvalidEmail, s)
+		}
+	}
+	if p.RejectDisposable && IsDisposableEmailDomain(address.Domain) {
+		return nil, fmt.Errorf("%w %q: disposable domain", ErrInvalidEmail, s)
+	}
+	return address, nil
+}
+
+func (p EmailPolicy) parseDomain(address *EmailAddress, domain string) error {
+	if strings.HasPrefix(domain, "[") {
+		if p.Strictness > EmailLenient {
+			return errors.New("domain literals are not allowed")
+		}
+		address.Domain, address.UnicodeDomain = domain, domain
+		return nil
+	}
+
+	ascii, err := idna.Lookup.ToASCII(domain)
+	if err != nil || ascii == "" {
+		return errors.New("invalid domain")
+	}
+	ascii = strings.ToLower(ascii)
+	if len(ascii) > 253 {
+		return errors.New("domain longer than 253 octets")
+	}
+
+	labels := strings.Split(ascii, ".")
+	for _, label := range labels {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return errors.New("invalid domain")
+		}
+	}
+	if p.Strictness >= EmailStandard {
+		tld := labels[len(labels)-1]
+		if len(labels) < 2 || len(tld) < 2 || isDigits(tld) {
+			return errors.New("domain needs a top-level domain")
+		}
+	}
+
+	unicode, err := idna.Lookup.ToUnicode(ascii)
+	if err != nil {
+		return errors.New("invalid domain")
+	}
+	address.Domain, address.UnicodeDomain = ascii, unicode
+	return nil
+}
+
+func isEmailAtext(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("!#$%&'*+-/=?^_`{|}~", r)
+}
+
+func isEmailDotAtom(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, atom := range strings.Split(s, ".") {
+		if atom == "" {
+			return false
+		}
+		for _, r := range atom {
+			if !isEmailAtext(r) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func quoteEmailLocalPart(local string) string {
+	if isEmailDotAtom(local) {
+		return local
+	}
+	// Unquoted UTF-8 is allowed by RFC 6531 as long as it is a dot-atom.
+	ascii := true
+	for _, r := range local {
+		if r > 0x7f {
+			ascii = false
+			break
+		}
+	}
+	if !ascii && !strings.ContainsAny(local, " \"\\(),:;<>@[]") && !strings.Contains(local, "..") &&
+		!strings.HasPrefix(local, ".") && !strings.HasSuffix(local, ".") {
+		return local
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(local) + `"`
+}
@@ -0,0 +1,105 @@
+package common
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEmail(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    EmailAddress
+		address string
+	}{
+		{"john@Example.COM", EmailAddress{LocalPart: "john", Domain: "example.com", UnicodeDomain: "example.com"}, "john@example.com"},
+		{"John Doe <john.doe+news@example.com>", EmailAddress{DisplayName: "John Doe", LocalPart: "john.doe+news", Domain: "example.com", UnicodeDomain: "example.com"}, "john.doe+news@example.com"},
+		{`"john doe"@example.com`, EmailAddress{LocalPart: "john doe", Domain: "example.com", UnicodeDomain: "example.com"}, `"john doe"@example.com`},
+		{"info@bücher.de", EmailAddress{LocalPart: "info", Domain: "xn--bcher-kva.de", UnicodeDomain: "bücher.de"}, "info@xn--bcher-kva.de"},
+		{"jozef@xn--bcher-kva.de", EmailAddress{LocalPart: "jozef", Domain: "xn--bcher-kva.de", UnicodeDomain: "bücher.de"}, "jozef@xn--bcher-kva.de"},
+		{"ján@príklad.sk", EmailAddress{LocalPart: "ján", Domain: "xn--prklad-4va.sk", UnicodeDomain: "príklad.sk"}, "ján@xn--prklad-4va.sk"},
+		{"user@example.photography", EmailAddress{LocalPart: "user", Domain: "example.photography", UnicodeDomain: "example.photography"}, "user@example.photography"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.value, func(t *testing.T) {
+			address, err := ParseEmail(tc.value)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, *address)
+			assert.Equal(t, tc.address, address.Address())
+		})
+	}
+}
+
+func TestParseEmail_Invalid(t *testing.T) {
+	for _, value := range []string{
+		"",
+		"anything",
+		"anything@domain",
+		"a..b@example.com",
+		".a@example.com",
+		"a@-example.com",
+		"a@example..com",
+		"a@example.1",
+		"a@[192.0.2.1]",
+		"a b@example.com",
+		strings.Repeat("a", 65) + "@example.com",
+		"a@" + strings.Repeat("b", 64) + ".com",
+		"a@" + strings.Repeat("bbbbbbbbb.", 25) + "com",
+	} {
+		_, err := ParseEmail(value)
+		assert.ErrorIs(t, err, ErrInvalidEmail, value)
+	}
+}
+
+func TestEmailPolicy_Strictness(t *testing.T) {
+	lenient := EmailPolicy{Strictness: EmailLenient}
+	strict := EmailPolicy{Strictness: EmailStrict, EnforceLengthLimits: true}
+
+	for _, value := range []string{"root@localhost", "a@[192.0.2.1]"} {
+		_, err := lenient.Parse(value)
+		assert.NoError(t, err, value)
+	}
+	for _, value := range []string{`"john doe"@example.com`, "ján@example.sk"} {
+		_, err := strict.Parse(value)
+		assert.ErrorIs(t, err, ErrInvalidEmail, value)
+	}
+	_, err := strict.Parse("john.doe@example.com")
+	assert.NoError(t, err)
+
+	long := strings.Repeat("a", 65) + "@example.com"
+	_, err = lenient.Parse(long)
+	assert.NoError(t, err)
+}
+
+func TestEmailPolicy_Disposable(t *testing.T) {
+	policy := DefaultEmailPolicy
+	policy.RejectDisposable = true
+
+	_, err := policy.Parse("x@mailinator.com")
+	assert.ErrorIs(t, err, ErrInvalidEmail)
+	_, err = policy.Parse("x@eu.Mailinator.com")
+	assert.ErrorIs(t, err, ErrInvalidEmail)
+	_, err = policy.Parse("x@example.com")
+	assert.NoError(t, err)
+
+	RegisterDisposableEmailDomains("Throwaway.Example")
+	assert.True(t, IsDisposableEmailDomain("throwaway.example"))
+	_, err = ParseEmail("x@throwaway.example")
+	assert.NoError(t, err, "the default policy does not check disposable domains")
+}
+
+func TestEmailAddress_String(t *testing.T) {
+	address, err := ParseEmail("Ján Novák <jan@example.sk>")
+	require.NoError(t, err)
+	assert.Equal(t, "=?utf-8?q?J=C3=A1n_Nov=C3=A1k?= <jan@example.sk>", address.String())
+}
+
+func TestIsValidEmail_Wrapper(t *testing.T) {
+	assert.True(t, IsValidEmail(`"quoted local"@example.com`))
+	assert.True(t, IsValidEmail("info@bücher.de"))
+	assert.False(t, IsValidEmail("John <john@example.com>"))
+	assert.False(t, IsValidEmail("a..b@example.com"))
+}
@@ -0,0 +1,189 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+// ErrorDefinition declares an error code once. Message and Translations are
+// templates with {name} placeholders filled from the parameters; Translations
+// are keyed by language tag.
+type ErrorDefinition struct {
+	Code         string            `json:"code"`
+	Status       int               `json:"status"`
+	Message      string            `json:"message"`
+	Translations map[string]string `json:"translations,omitempty"`
+}
+
+type catalogEntry struct {
+	definition ErrorDefinition
+	tags       []language.Tag
+	templates  []string
+	matcher    language.Matcher
+}
+
+type ErrorCatalog struct {
+	mu              sync.RWMutex
+	defaultLanguage language.Tag
+	entries         map[string]*catalogEntry
+}
+
+// NewErrorCatalog creates a catalog whose default messages are written in
+// defaultLanguage, e.g. "en".
+func NewErrorCatalog(defaultLanguage string) (*ErrorCatalog, error) {
+	tag, err := language.Parse(defaultLanguage)
+	if err != nil {
+		return nil, fmt.Errorf("invalid default language %q: %w", defaultLanguage, err)
+	}
+	return &ErrorCatalog{defaultLanguage: tag, entries: make(map[string]*catalogEntry)}, nil
+}
+
+// Register adds definitions; a code may be declared only once.
+func (c *ErrorCatalog) Register(definitions ...ErrorDefinition) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries := make(map[string]*catalogEntry, len(definitions))
+	for _, definition := range definitions {
+		if IsBlank(definition.Code) {
+			return errors.New("error code must not be blank")
+		}
+		if _, ok := c.entries[definition.Code]; ok {
+			return fmt.Errorf("duplicate error code %s", definition.Code)
+		}
+		if _, ok := entries[definition.Code]; ok {
+			return fmt.Errorf("duplicate error code %s", definition.Code)
+		}
+		if definition.Status < 400 || definition.Status > 599 {
+			return fmt.Errorf("error code %s has invalid status %d", definition.Code, definition.Status)
+		}
+
+		entry := &catalogEntry{
+			definition: definition,
+			tags:       []language.Tag{c.defaultLanguage},
+			templates:  []string{definition.Message},
+		}
+		if len(definition.Translations) > 0 {
+			entry.definition.Translations = make(map[string]string, len(definition.Translations))
+		}
+		for _, key := range slices.Sorted(maps.Keys(definition.Translations)) {
+			tag, err := language.Parse(key)
+			if err != nil {
+				return fmt.Errorf("error code %s has invalid language %q: %w", definition.Code, key, err)
+			}
+			entry.definition.Translations[tag.String()] = definition.Translations[key]
+			entry.tags = append(entry.tags, tag)
+			entry.templates = append(entry.templates, definition.Translations[key])
+		}
+		entry.matcher = language.NewMatcher(entry.tags)
+		entries[definition.Code] = entry
+	}
+
+	maps.Copy(c.entries, entries)
+	return nil
+}
+
+func (c *ErrorCatalog) MustRegister(definitions ...ErrorDefinition) {
+	if err := c.Register(definitions...); err != nil {
+		panic(err)
+	}
+}
+
+// New builds a ServiceError for code with its default message. The params are
+// kept as metadata so Localize can render the message again. An unknown code
+// gives a generic 500 error so a typo does not hide the failure; the code is
+// kept in Cause and Metadata for logs only.
+func (c *ErrorCatalog) New(code string, params map[string]any) *ServiceError {
+	c.mu.RLock()
+	entry, ok := c.entries[code]
+	c.mu.RUnlock()
+	if !ok {
+		se := newServiceError(http.StatusInternalServerError, "INTERNAL_ERROR",
+			http.StatusText(http.StatusInternalServerError), fmt.Errorf("unknown error code %q", code))
+		se.Metadata = map[string]any{"code": code}
+		return se
+	}
+
+	se := newServiceError(entry.definition.Status, code, renderErrorTemplate(entry.definition.Message, params), nil)
+	if len(params) > 0 {
+		se.Metadata = maps.Clone(params)
+	}
+	return se
+}
+
+// Message renders code in the best language for an Accept-Language header.
+func (c *ErrorCatalog) Message(code string, acceptLanguage string, params map[string]any) (string, bool) {
+	c.mu.RLock()
+	entry, ok := c.entries[code]
+	c.mu.RUnlock()
+	if !ok {
+		return "", false
+	}
+
+	template := entry.definition.Message
+	if accepted, _, err := language.ParseAcceptLanguage(acceptLanguage); err == nil && len(accepted) > 0 {
+		_, index, confidence := entry.matcher.Match(accepted...)
+		if confidence != language.No {
+			template = entry.templates[index]
+		}
+	}
+	return renderErrorTemplate(template, params), true
+}
+
+// Localize returns a copy of se with the message in the best language for an
+// Accept-Language header. Errors with codes outside the catalog are returned
+// as they are.
+func (c *ErrorCatalog) Localize(se *ServiceError, acceptLanguage string) *ServiceError {
+	message, ok := c.Message(se.Code, acceptLanguage, se.Metadata)
+	if !ok {
+		return se
+	}
+	localized := *se
+	localized.Message = message
+	return &localized
+}
+
+// WriteProblem is WriteProblem with the message localized for r.
+func (c *ErrorCatalog) WriteProblem(w http.ResponseWriter, r *http.Request, err error) error {
+	problem := NewProblem(err)
+	var se *ServiceError
+	if errors.As(err, &se) {
+		if message, ok := c.Message(se.Code, r.Header.Get("Accept-Language"), se.Metadata); ok {
+			problem.Detail = message
+		}
+	}
+	problem.Instance = r.URL.Path
+	return problem.Write(w)
+}
+
+// Export lists the definitions sorted by code, e.g. for frontend bundles.
+func (c *ErrorCatalog) Export() []ErrorDefinition {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := make([]ErrorDefinition, 0, len(c.entries))
+	for _, code := range slices.Sorted(maps.Keys(c.entries)) {
+		definition := c.entries[code].definition
+		definition.Translations = maps.Clone(definition.Translations)
+		result = append(result, definition)
+	}
+	return result
+}
+
+func renderErrorTemplate(template string, params map[string]any) string {
+	if len(params) == 0 {
+		return template
+	}
+	pairs := make([]string, 0, len(params)*2)
+	for key, value := range params {
+		pairs = append(pairs, "{"+key+"}", fmt.Sprint(value))
+	}
+	return strings.NewReplacer(pairs...).Replace(template)
+}
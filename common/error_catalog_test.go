@@ -0,0 +1,119 @@
+package common
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestErrorCatalog(t *testing.T) *ErrorCatalog {
+	catalog, err := NewErrorCatalog("en")
+	require.NoError(t, err)
+	catalog.MustRegister(
+		ErrorDefinition{
+			Code:    "USER_NOT_FOUND",
+			Status:  http.StatusNotFound,
+			Message: "User {id} not found",
+			Translations: map[string]string{
+				"sk":    "Používateľ {id} neexistuje",
+				"de_AT": "Benutzer {id} nicht gefunden",
+			},
+		},
+		ErrorDefinition{Code: "LIMIT_EXCEEDED", Status: http.StatusTooManyRequests, Message: "Limit of {limit} exceeded"},
+	)
+	return catalog
+}
+
+func TestErrorCatalog_New(t *testing.T) {
+	catalog := newTestErrorCatalog(t)
+
+	se := catalog.New("USER_NOT_FOUND", map[string]any{"id": 42})
+	assert.Equal(t, http.StatusNotFound, se.Status)
+	assert.Equal(t, "User 42 not found", se.Message)
+	assert.True(t, IsCode(se, "USER_NOT_FOUND"))
+
+	unknown := catalog.New("NOPE", nil)
+	assert.Equal(t, http.StatusInternalServerError, unknown.Status)
+	assert.Equal(t, "INTERNAL_ERROR", unknown.Code)
+	assert.Equal(t, "NOPE", unknown.Metadata["code"])
+	assert.ErrorContains(t, unknown.Cause, "NOPE")
+
+	rec := httptest.NewRecorder()
+	catalog.WriteProblem(rec, httptest.NewRequest(http.MethodGet, "/users/1", nil), unknown)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "NOPE")
+	assert.Contains(t, rec.Body.String(), `"detail":"Internal Server Error"`)
+}
+
+func TestErrorCatalog_Localize(t *testing.T) {
+	catalog := newTestErrorCatalog(t)
+	se := catalog.New("USER_NOT_FOUND", map[string]any{"id": 7})
+
+	tests := []struct {
+		acceptLanguage string
+		want           string
+	}{
+		{"", "User 7 not found"},
+		{"sk-SK,sk;q=0.9,en;q=0.8", "Používateľ 7 neexistuje"},
+		{"de-AT", "Benutzer 7 nicht gefunden"},
+		{"fr, sk;q=0.5", "Používateľ 7 neexistuje"},
+		{"ja", "User 7 not found"},
+		{"not a header;;", "User 7 not found"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.acceptLanguage, func(t *testing.T) {
+			assert.Equal(t, tc.want, catalog.Localize(se, tc.acceptLanguage).Message)
+		})
+	}
+	assert.Equal(t, "User 7 not found", se.Message, "Localize must not modify the original")
+
+	other := NotFound("OTHER", "other")
+	assert.Same(t, other, catalog.Localize(other, "sk"))
+}
+
+func TestErrorCatalog_Register(t *testing.T) {
+	catalog := newTestErrorCatalog(t)
+
+	assert.ErrorContains(t, catalog.Register(ErrorDefinition{Code: "USER_NOT_FOUND", Status: 404}), "duplicate")
+	assert.ErrorContains(t, catalog.Register(
+		ErrorDefinition{Code: "A", Status: 400},
+		ErrorDefinition{Code: "A", Status: 400},
+	), "duplicate")
+	assert.Error(t, catalog.Register(ErrorDefinition{Code: " ", Status: 400}))
+	assert.Error(t, catalog.Register(ErrorDefinition{Code: "B", Status: 200}))
+	assert.Error(t, catalog.Register(ErrorDefinition{Code: "C", Status: 400, Translations: map[string]string{"???": "x"}}))
+	assert.Len(t, catalog.Export(), 2, "failed registrations must not add codes")
+
+	_, err := NewErrorCatalog("!")
+	assert.Error(t, err)
+}
+
+func TestErrorCatalog_Export(t *testing.T) {
+	data, err := json.Marshal(newTestErrorCatalog(t).Export())
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"code": "LIMIT_EXCEEDED", "status": 429, "message": "Limit of {limit} exceeded"},
+		{"code": "USER_NOT_FOUND", "status": 404, "message": "User {id} not found",
+		 "translations": {"de-AT": "Benutzer {id} nicht gefunden", "sk": "Používateľ {id} neexistuje"}}
+	]`, string(data))
+}
+
+func TestErrorCatalog_WriteProblem(t *testing.T) {
+	catalog := newTestErrorCatalog(t)
+	r := httptest.NewRequest(http.MethodGet, "/users/3", nil)
+	r.Header.Set("Accept-Language", "sk")
+
+	w := httptest.NewRecorder()
+	require.NoError(t, catalog.WriteProblem(w, r, catalog.New("USER_NOT_FOUND", map[string]any{"id": 3})))
+
+	var problem Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, http.StatusNotFound, problem.Status)
+	assert.Equal(t, "Používateľ 3 neexistuje", problem.Detail)
+	assert.Equal(t, "/users/3", problem.Instance)
+}
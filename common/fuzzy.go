@@ -0,0 +1,228 @@
+package common
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Tokenize splits text into lower case words of letters and digits, the word
+// rule of pg_trgm.
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// NGrams returns the character n-grams of s in order, with duplicates.
+func NGrams(s string, n int) []string {
+	r := []rune(s)
+	if n <= 0 || len(r) < n {
+		return nil
+	}
+	result := make([]string, 0, len(r)-n+1)
+	for i := 0; i+n <= len(r); i++ {
+		result = append(result, string(r[i:i+n]))
+	}
+	return result
+}
+
+// Trigrams returns the sorted, unique trigrams of text as PostgreSQL pg_trgm
+// computes them (show_trgm): every word is padded with two spaces in front and
+// one behind, so "cat" gives "  c", " ca", "at ", "cat".
+func Trigrams(text string) []string {
+	var result []string
+	for _, word := range Tokenize(text) {
+		result = append(result, NGrams("  "+word+" ", 3)...)
+	}
+	slices.Sort(result)
+	return slices.Compact(result)
+}
+
+// TrigramSimilarity matches pg_trgm similarity(a, b): shared trigrams divided
+// by all distinct trigrams.
+func TrigramSimilarity(a, b string) float64 {
+	return trigramSimilarity(Trigrams(a), Trigrams(b))
+}
+
+func trigramSimilarity(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch strings.Compare(a[i], b[j]) {
+		case 0:
+			shared++
+			i++
+			j++
+		case -1:
+			i++
+		default:
+			j++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// Levenshtein returns the number of single character insertions, deletions
+// and substitutions turning a into b.
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// JaroWinkler returns the Jaro-Winkler similarity between 0 and 1, favouring
+// strings with a common prefix of up to four characters.
+func JaroWinkler(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+
+	window := max(0, max(len(ra), len(rb))/2-1)
+	matchedA := make([]bool, len(ra))
+	matchedB := make([]bool, len(rb))
+	matches := 0
+	for i := range ra {
+		for j := max(0, i-window); j < min(len(rb), i+window+1); j++ {
+			if !matchedB[j] && ra[i] == rb[j] {
+				matchedA[i], matchedB[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions := 0
+	for i, j := 0, 0; i < len(ra); i++ {
+		if !matchedA[i] {
+			continue
+		}
+		for !matchedB[j] {
+			j++
+		}
+		if ra[i] != rb[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(ra)) + m/float64(len(rb)) + (m-float64(transpositions/2))/m) / 3
+
+	prefix := 0
+	for prefix < min(4, len(ra), len(rb)) && ra[prefix] == rb[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+type FuzzyMatch[T any] struct {
+	Item  T
+	Score float64
+}
+
+// FuzzyMatcher ranks items for autocomplete. Keys and queries are compared
+// after NormalizeForSearch, so "lodz" finds "Łódź".
+type FuzzyMatcher[T any] struct {
+	// MinScore drops weaker matches, 0.3 by default like pg_trgm.
+	MinScore float64
+	items    []T
+	keys     []string
+	words    [][]string
+	trigrams [][]string
+}
+
+func NewFuzzyMatcher[T any](items []T, key func(T) string) *FuzzyMatcher[T] {
+	m := &FuzzyMatcher[T]{
+		MinScore: 0.3,
+		items:    slices.Clone(items),
+		keys:     make([]string, len(items)),
+		words:    make([][]string, len(items)),
+		trigrams: make([][]string, len(items)),
+	}
+	for i, item := range items {
+		normalized := NormalizeForSearch(key(item))
+		m.keys[i] = normalized
+		m.words[i] = Tokenize(normalized)
+		m.trigrams[i] = Trigrams(normalized)
+	}
+	return m
+}
+
+// Search returns up to limit items ordered by score, best first; ties keep
+// the item order. A limit of 0 returns all matches.
+//
+// An exact match scores 1, a prefix of the key 0.9 and a prefix of a word in
+// the key 0.8; otherwise the score is the better of trigram similarity and,
+// for typos, the Jaro-Winkler similarity of the closest word scaled by 0.75
+// when it reaches 0.85.
+func (m *FuzzyMatcher[T]) Search(query string, limit int) []FuzzyMatch[T] {
+	query = NormalizeForSearch(query)
+	if query == "" {
+		return nil
+	}
+	queryTrigrams := Trigrams(query)
+
+	var result []FuzzyMatch[T]
+	for i, key := range m.keys {
+		score := m.score(i, key, query, queryTrigrams)
+		if score >= m.MinScore && score > 0 {
+			result = append(result, FuzzyMatch[T]{Item: m.items[i], Score: score})
+		}
+	}
+
+	slices.SortStableFunc(result, func(a, b FuzzyMatch[T]) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+func (m *FuzzyMatcher[T]) score(i int, key, query string, queryTrigrams []string) float64 {
+	switch {
+	case key == query:
+		return 1
+	case strings.HasPrefix(key, query):
+		return 0.9
+	}
+	for _, word := range m.words[i] {
+		if strings.HasPrefix(word, query) {
+			return 0.8
+		}
+	}
+
+	typo := 0.0
+	for _, word := range m.words[i] {
+		if similarity := JaroWinkler(word, query); similarity >= 0.85 {
+			typo = max(typo, similarity*0.75)
+		}
+	}
+	return max(trigramSimilarity(m.trigrams[i], queryTrigrams), typo)
+}
@@ -0,0 +1,92 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, []string{"hello", "world", "42"}, Tokenize(" Hello, world-42! "))
+	assert.Equal(t, []string{"žltý", "kôň"}, Tokenize("Žltý  kôň"))
+	assert.Empty(t, Tokenize(" ,.- "))
+}
+
+func TestNGrams(t *testing.T) {
+	assert.Equal(t, []string{"ab", "bc", "cd"}, NGrams("abcd", 2))
+	assert.Equal(t, []string{"kôň"}, NGrams("kôň", 3))
+	assert.Nil(t, NGrams("ab", 3))
+	assert.Nil(t, NGrams("ab", 0))
+}
+
+func TestTrigrams(t *testing.T) {
+	assert.Equal(t, []string{"  c", " ca", "at ", "cat"}, Trigrams("cat"))
+	assert.Equal(t, []string{"  a", "  b", " a ", " b "}, Trigrams("a b a"))
+	assert.Empty(t, Trigrams(""))
+}
+
+func TestTrigramSimilarity(t *testing.T) {
+	assert.InDelta(t, 0.571429, TrigramSimilarity("word", "words"), 1e-6)
+	assert.InDelta(t, 1.0, TrigramSimilarity("Word", "word!"), 1e-9)
+	assert.Zero(t, TrigramSimilarity("abc", "xyz"))
+	assert.Zero(t, TrigramSimilarity("", "xyz"))
+}
+
+func TestLevenshtein(t *testing.T) {
+	assert.Equal(t, 3, Levenshtein("kitten", "sitting"))
+	assert.Equal(t, 0, Levenshtein("", ""))
+	assert.Equal(t, 3, Levenshtein("", "abc"))
+	assert.Equal(t, 1, Levenshtein("kôň", "koň"))
+}
+
+func TestJaroWinkler(t *testing.T) {
+	assert.InDelta(t, 0.961111, JaroWinkler("MARTHA", "MARHTA"), 1e-6)
+	assert.InDelta(t, 0.813333, JaroWinkler("DIXON", "DICKSONX"), 1e-6)
+	assert.InDelta(t, 0.840000, JaroWinkler("DWAYNE", "DUANE"), 1e-6)
+	assert.Equal(t, 1.0, JaroWinkler("", ""))
+	assert.Equal(t, 1.0, JaroWinkler("a", "a"))
+	assert.Equal(t, 1.0, JaroWinkler("ž", "ž"))
+	assert.Equal(t, 0.0, JaroWinkler("a", "b"))
+	assert.InDelta(t, 0.85, JaroWinkler("a", "ab"), 1e-9)
+	assert.Equal(t, 0.0, JaroWinkler("abc", ""))
+	assert.Equal(t, 0.0, JaroWinkler("abc", "xyz"))
+}
+
+type fuzzyTestCity struct {
+	ID   int
+	Name string
+}
+
+func TestFuzzyMatcher_Search(t *testing.T) {
+	cities := []fuzzyTestCity{
+		{1, "Bratislava"},
+		{2, "Banská Bystrica"},
+		{3, "Łódź"},
+		{4, "Bystřice"},
+		{5, "Košice"},
+	}
+	matcher := NewFuzzyMatcher(cities, func(c fuzzyTestCity) string { return c.Name })
+
+	ids := func(matches []FuzzyMatch[fuzzyTestCity]) []int {
+		result := make([]int, len(matches))
+		for i, match := range matches {
+			result[i] = match.Item.ID
+		}
+		return result
+	}
+
+	assert.Equal(t, []int{3}, ids(matcher.Search("lodz", 0)))
+	assert.Equal(t, []int{4, 2}, ids(matcher.Search("byst", 0)))
+	assert.Equal(t, []int{1}, ids(matcher.Search("Bratislava", 1)))
+	assert.Equal(t, []int{5}, ids(matcher.Search("kosicee", 1)))
+
+	matches := matcher.Search("bratslava", 0)
+	if assert.NotEmpty(t, matches) {
+		assert.Equal(t, 1, matches[0].Item.ID)
+		assert.Less(t, matches[0].Score, 0.8)
+	}
+
+	assert.Len(t, matcher.Search("b", 2), 2)
+	assert.Empty(t, matcher.Search("  ", 0))
+	assert.Empty(t, matcher.Search("xyz", 0))
+}
@@ -0,0 +1,303 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+)
+
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+type Currency struct {
+	Code       string
+	MinorUnits int
+	Symbol     string
+}
+
+var currencies = struct {
+	sync.RWMutex
+	byCode map[string]Currency
+}{byCode: make(map[string]Currency)}
+
+func init() {
+	minorUnits := map[int]string{
+		0: "BIF CLP DJF GNF ISK JPY KMF KRW PYG RWF UGX UYI VND VUV XAF XOF XPF",
+		2: "AED AFN ALL AMD ANG AOA ARS AUD AWG AZN BAM BBD BDT BGN BMD BND BOB BRL BSD BTN BWP BYN BZD " +
+			"CAD CDF CHF CNY COP CRC CUP CVE CZK DKK DOP DZD EGP ERN ETB EUR FJD FKP GBP GEL GHS GIP GMD " +
+			"GTQ GYD HKD HNL HTG HUF IDR ILS INR IRR JMD KES KGS KHR KPW KYD KZT LAK LBP LKR LRD LSL MAD " +
+			"MDL MGA MKD MMK MNT MOP MRU MUR MVR MWK MXN MYR MZN NAD NGN NIO NOK NPR NZD PAB PEN PGK PHP " +
+			"PKR PLN QAR RON RSD RUB SAR SBD SCR SDG SEK SGD SHP SLE SOS SRD SSP STN SVC SYP SZL THB TJS " +
+			"TMT TOP TRY TTD TWD TZS UAH USD UYU UZS VED VES WST XCD YER ZAR ZMW ZWG",
+		3: "BHD IQD JOD KWD LYD OMR TND",
+		4: "CLF UYW",
+	}
+	symbols := map[string]string{
+		"BRL": "R$", "CNY": "¥", "CZK": "Kč", "EUR": "€", "GBP": "£", "HUF": "Ft", "ILS": "₪",
+		"INR": "₹", "JPY": "¥", "KRW": "₩", "PLN": "zł", "RUB": "₽", "TRY": "₺", "UAH": "₴", "USD": "$",
+	}
+
+	for units, codes := range minorUnits {
+		for _, code := range strings.Fields(codes) {
+			symbol, ok := symbols[code]
+			if !ok {
+				symbol = code
+			}
+			currencies.byCode[code] = Currency{Code: code, MinorUnits: units, Symbol: symbol}
+		}
+	}
+}
+
+// RegisterCurrency adds or replaces a currency, e.g. for non-ISO units.
+func RegisterCurrency(currency Currency) error {
+	code := strings.ToUpper(strings.TrimSpace(currency.Code))
+	if code == "" {
+		return errors.New("currency code must not be blank")
+	}
+	if currency.MinorUnits < 0 {
+		return fmt.Errorf("currency %s minor units must be >= 0, got %d", code, currency.MinorUnits)
+	}
+	currency.Code = code
+	if currency.Symbol == "" {
+		currency.Symbol = code
+	}
+
+	currencies.Lock()
+	defer currencies.Unlock()
+	currencies.byCode[code] = currency
+	return nil
+}
+
+func LookupCurrency(code string) (Currency, error) {
+	currencies.RLock()
+	defer currencies.RUnlock()
+	currency, ok := currencies.byCode[strings.ToUpper(strings.TrimSpace(code))]
+	if !ok {
+		return Currency{}, fmt.Errorf("unknown currency %q", code)
+	}
+	return currency, nil
+}
+
+func MustCurrency(code string) Currency {
+	currency, err := LookupCurrency(code)
+	if err != nil {
+		panic(err)
+	}
+	return currency
+}
+
+// Money is an amount at the minor unit scale of its currency.
+type Money struct {
+	amount   Decimal
+	currency Currency
+}
+
+// NewMoney fails when amount has more fraction digits than the currency allows;
+// use NewMoneyRounded to round instead.
+func NewMoney(amount Decimal, code string) (Money, error) {
+	return NewMoneyRounded(amount, code, RoundUnnecessary)
+}
+
+func NewMoneyRounded(amount Decimal, code string, mode RoundingMode) (Money, error) {
+	currency, err := LookupCurrency(code)
+	if err != nil {
+		return Money{}, err
+	}
+	scaled, err := amount.Rescale(currency.MinorUnits, mode)
+	if err != nil {
+		return Money{}, fmt.Errorf("amount %s does not fit currency %s: %w", amount, currency.Code, err)
+	}
+	return Money{amount: scaled, currency: currency}, nil
+}
+
+func ParseMoney(amount, code string) (Money, error) {
+	d, err := ParseDecimal(amount)
+	if err != nil {
+		return Money{}, err
+	}
+	return NewMoney(d, code)
+}
+
+func MustParseMoney(amount, code string) Money {
+	m, err := ParseMoney(amount, code)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func MoneyFromMinor(minor int64, code string) (Money, error) {
+	currency, err := LookupCurrency(code)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{amount: NewDecimal(minor, currency.MinorUnits), currency: currency}, nil
+}
+
+func ZeroMoney(code string) (Money, error) {
+	return MoneyFromMinor(0, code)
+}
+
+func (m Money) Amount() Decimal {
+	return m.amount
+}
+
+func (m Money) Currency() Currency {
+	return m.currency
+}
+
+func (m Money) MinorAmount() *big.Int {
+	return m.amount.Unscaled()
+}
+
+func (m Money) Sign() int {
+	return m.amount.Sign()
+}
+
+func (m Money) IsZero() bool {
+	return m.amount.IsZero()
+}
+
+func (m Money) Neg() Money {
+	return Money{amount: m.amount.Neg(), currency: m.currency}
+}
+
+func (m Money) Abs() Money {
+	return Money{amount: m.amount.Abs(), currency: m.currency}
+}
+
+func (m Money) checkCurrency(o Money) error {
+	if m.currency.Code != o.currency.Code {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.currency.Code, o.currency.Code)
+	}
+	return nil
+}
+
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.checkCurrency(o); err != nil {
+		return Money{}, err
+	}
+	return Money{amount: m.amount.Add(o.amount), currency: m.currency}, nil
+}
+
+func (m Money) Sub(o Money) (Money, error) {
+	if err := m.checkCurrency(o); err != nil {
+		return Money{}, err
+	}
+	return Money{amount: m.amount.Sub(o.amount), currency: m.currency}, nil
+}
+
+func (m Money) Mul(factor Decimal, mode RoundingMode) (Money, error) {
+	amount, err := m.amount.MulRound(factor, m.currency.MinorUnits, mode)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{amount: amount, currency: m.currency}, nil
+}
+
+func (m Money) Cmp(o Money) (int, error) {
+	if err := m.checkCurrency(o); err != nil {
+		return 0, err
+	}
+	return m.amount.Cmp(o.amount), nil
+}
+
+func (m Money) Equal(o Money) bool {
+	return m.currency.Code == o.currency.Code && m.amount.Equal(o.amount)
+}
+
+// Allocate distributes m across ratios without losing a minor unit. Leftover
+// minor units go one by one to the first parts, so Allocate(1, 1, 1) of 0.10
+// yields 0.04, 0.03, 0.03.
+func (m Money) Allocate(ratios ...int64) ([]Money, error) {
+	if len(ratios) == 0 {
+		return nil, errors.New("at least one ratio is required")
+	}
+
+	total := new(big.Int)
+	for _, ratio := range ratios {
+		if ratio < 0 {
+			return nil, fmt.Errorf("ratio must be >= 0, got %d", ratio)
+		}
+		total.Add(total, big.NewInt(ratio))
+	}
+	if total.Sign() == 0 {
+		return nil, errors.New("sum of ratios must be > 0")
+	}
+
+	minor := m.MinorAmount()
+	sign := minor.Sign()
+	minor.Abs(minor)
+
+	parts := make([]*big.Int, len(ratios))
+	remainder := new(big.Int).Set(minor)
+	for i, ratio := range ratios {
+		parts[i] = new(big.Int).Mul(minor, big.NewInt(ratio))
+		parts[i].Quo(parts[i], total)
+		remainder.Sub(remainder, parts[i])
+	}
+	for i := 0; remainder.Sign() > 0; i = (i + 1) % len(parts) {
+		if ratios[i] == 0 {
+			continue
+		}
+		parts[i].Add(parts[i], big.NewInt(1))
+		remainder.Sub(remainder, big.NewInt(1))
+	}
+
+	result := make([]Money, len(parts))
+	for i, part := range parts {
+		if sign < 0 {
+			part.Neg(part)
+		}
+		result[i] = Money{amount: NewDecimalFromBigInt(part, m.currency.MinorUnits), currency: m.currency}
+	}
+	return result, nil
+}
+
+func (m Money) Split(n int) ([]Money, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("number of parts must be > 0, got %d", n)
+	}
+	ratios := make([]int64, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+	return m.Allocate(ratios...)
+}
+
+// String returns the amount followed by the currency code, e.g. "12.30 EUR".
+func (m Money) String() string {
+	return m.amount.String() + " " + m.currency.Code
+}
+
+// FormatSymbol returns the amount prefixed with the currency symbol, e.g. "€12.30".
+func (m Money) FormatSymbol() string {
+	if m.Sign() < 0 {
+		return "-" + m.currency.Symbol + m.amount.Abs().String()
+	}
+	return m.currency.Symbol + m.amount.String()
+}
+
+type moneyJson struct {
+	Amount   Decimal `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJson{Amount: m.amount, Currency: m.currency.Code})
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var raw moneyJson
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	parsed, err := NewMoney(raw.Amount, raw.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
@@ -0,0 +1,166 @@
+package common
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func moneyStrings(values []Money) []string {
+	result := make([]string, len(values))
+	for i, v := range values {
+		result[i] = v.Amount().String()
+	}
+	return result
+}
+
+func TestLookupCurrency(t *testing.T) {
+	eur, err := LookupCurrency("eur")
+	require.NoError(t, err)
+	assert.Equal(t, Currency{Code: "EUR", MinorUnits: 2, Symbol: "€"}, eur)
+
+	assert.Equal(t, 0, MustCurrency("JPY").MinorUnits)
+	assert.Equal(t, 3, MustCurrency("KWD").MinorUnits)
+	assert.Equal(t, "CHF", MustCurrency("CHF").Symbol)
+
+	_, err = LookupCurrency("XXX1")
+	assert.Error(t, err)
+}
+
+func TestRegisterCurrency(t *testing.T) {
+	require.NoError(t, RegisterCurrency(Currency{Code: "pts", MinorUnits: 0}))
+	pts := MustCurrency("PTS")
+	assert.Equal(t, "PTS", pts.Symbol)
+
+	assert.Error(t, RegisterCurrency(Currency{Code: " "}))
+	assert.Error(t, RegisterCurrency(Currency{Code: "NEG", MinorUnits: -1}))
+}
+
+func TestNewMoney(t *testing.T) {
+	m, err := NewMoney(MustParseDecimal("12.5"), "EUR")
+	require.NoError(t, err)
+	assert.Equal(t, "12.50 EUR", m.String())
+	assert.Equal(t, "1250", m.MinorAmount().String())
+
+	_, err = NewMoney(MustParseDecimal("12.345"), "EUR")
+	assert.ErrorIs(t, err, ErrRoundingNecessary)
+
+	m, err = NewMoneyRounded(MustParseDecimal("12.345"), "EUR", RoundHalfEven)
+	require.NoError(t, err)
+	assert.Equal(t, "12.34 EUR", m.String())
+
+	m, err = MoneyFromMinor(1999, "JPY")
+	require.NoError(t, err)
+	assert.Equal(t, "1999 JPY", m.String())
+
+	zero, err := ZeroMoney("KWD")
+	require.NoError(t, err)
+	assert.Equal(t, "0.000 KWD", zero.String())
+
+	_, err = ParseMoney("1", "ABC")
+	assert.Error(t, err)
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	a := MustParseMoney("10.10", "EUR")
+	b := MustParseMoney("0.95", "EUR")
+
+	sum, err := a.Add(b)
+	require.NoError(t, err)
+	assert.Equal(t, "11.05 EUR", sum.String())
+
+	diff, err := b.Sub(a)
+	require.NoError(t, err)
+	assert.Equal(t, "-9.15 EUR", diff.String())
+
+	product, err := a.Mul(MustParseDecimal("0.2"), RoundHalfUp)
+	require.NoError(t, err)
+	assert.Equal(t, "2.02 EUR", product.String())
+
+	cmp, err := a.Cmp(b)
+	require.NoError(t, err)
+	assert.Equal(t, 1, cmp)
+
+	assert.True(t, a.Equal(MustParseMoney("10.1", "EUR")))
+	assert.False(t, a.Equal(MustParseMoney("10.10", "USD")))
+}
+
+func TestMoney_CurrencyMismatch(t *testing.T) {
+	eur := MustParseMoney("1", "EUR")
+	usd := MustParseMoney("1", "USD")
+
+	_, err := eur.Add(usd)
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+	_, err = eur.Sub(usd)
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+	_, err = eur.Cmp(usd)
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+}
+
+func TestMoney_Allocate(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount string
+		ratios []int64
+		want   []string
+	}{
+		{"even thirds", "0.10", []int64{1, 1, 1}, []string{"0.04", "0.03", "0.03"}},
+		{"weighted", "0.05", []int64{3, 7}, []string{"0.02", "0.03"}},
+		{"percentages", "100.00", []int64{50, 30, 20}, []string{"50.00", "30.00", "20.00"}},
+		{"zero ratio", "1.00", []int64{0, 1, 2}, []string{"0.00", "0.34", "0.66"}},
+		{"negative", "-0.10", []int64{1, 1, 1}, []string{"-0.04", "-0.03", "-0.03"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := MustParseMoney(tc.amount, "EUR")
+			parts, err := m.Allocate(tc.ratios...)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, moneyStrings(parts))
+
+			total := MustParseMoney("0", "EUR")
+			for _, part := range parts {
+				total, _ = total.Add(part)
+			}
+			assert.True(t, total.Equal(m), "allocation must not lose a cent")
+		})
+	}
+
+	m := MustParseMoney("1", "EUR")
+	_, err := m.Allocate()
+	assert.Error(t, err)
+	_, err = m.Allocate(0, 0)
+	assert.Error(t, err)
+	_, err = m.Allocate(1, -1)
+	assert.Error(t, err)
+}
+
+func TestMoney_Split(t *testing.T) {
+	parts, err := MustParseMoney("100", "JPY").Split(3)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"34", "33", "33"}, moneyStrings(parts))
+
+	_, err = MustParseMoney("100", "JPY").Split(0)
+	assert.Error(t, err)
+}
+
+func TestMoney_Format(t *testing.T) {
+	assert.Equal(t, "€12.30", MustParseMoney("12.3", "EUR").FormatSymbol())
+	assert.Equal(t, "-$0.05", MustParseMoney("-0.05", "USD").FormatSymbol())
+	assert.Equal(t, "CHF1.00", MustParseMoney("1", "CHF").FormatSymbol())
+}
+
+func TestMoney_Json(t *testing.T) {
+	data, err := json.Marshal(MustParseMoney("12.3", "EUR"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount":12.30,"currency":"EUR"}`, string(data))
+
+	var m Money
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":"5.5","currency":"usd"}`), &m))
+	assert.Equal(t, "5.50 USD", m.String())
+
+	assert.Error(t, json.Unmarshal([]byte(`{"amount":"5.555","currency":"USD"}`), &m))
+	assert.Error(t, json.Unmarshal([]byte(`{"amount":"5","currency":"NOPE"}`), &m))
+}
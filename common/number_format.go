@@ -0,0 +1,206 @@
+package common
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+const (
+	nbsp       = "\u00a0"
+	narrowNbsp = "\u202f"
+)
+
+// NumberLocale holds the rules for rendering numbers in one language. Patterns
+// use {n} for the number, {s} for the currency symbol and {v} for the whole
+// formatted positive value.
+type NumberLocale struct {
+	Tag             string
+	DecimalSep      string
+	GroupSep        string
+	NegativePattern string
+	PercentPattern  string
+	CurrencyPattern string
+}
+
+var numberLocales = struct {
+	sync.RWMutex
+	byTag map[string]NumberLocale
+}{byTag: map[string]NumberLocale{
+	"en": {Tag: "en", DecimalSep: ".", GroupSep: ",", NegativePattern: "-{v}", PercentPattern: "{n}%", CurrencyPattern: "{s}{n}"},
+	"de": {Tag: "de", DecimalSep: ",", GroupSep: ".", NegativePattern: "-{v}", PercentPattern: "{n}" + nbsp + "%", CurrencyPattern: "{n}" + nbsp + "{s}"},
+	"sk": {Tag: "sk", DecimalSep: ",", GroupSep: nbsp, NegativePattern: "-{v}", PercentPattern: "{n}" + nbsp + "%", CurrencyPattern: "{n}" + nbsp + "{s}"},
+	"cs": {Tag: "cs", DecimalSep: ",", GroupSep: nbsp, NegativePattern: "-{v}", PercentPattern: "{n}" + nbsp + "%", CurrencyPattern: "{n}" + nbsp + "{s}"},
+	"fr": {Tag: "fr", DecimalSep: ",", GroupSep: narrowNbsp, NegativePattern: "-{v}", PercentPattern: "{n}" + narrowNbsp + "%", CurrencyPattern: "{n}" + nbsp + "{s}"},
+}}
+
+func RegisterNumberLocale(locale NumberLocale) error {
+	tag := strings.ToLower(strings.TrimSpace(locale.Tag))
+	if tag == "" || locale.DecimalSep == "" || locale.DecimalSep == locale.GroupSep {
+		return fmt.Errorf("invalid number locale %q", locale.Tag)
+	}
+	locale.Tag = tag
+	if locale.NegativePattern == "" {
+		locale.NegativePattern = "-{v}"
+	}
+	if locale.PercentPattern == "" {
+		locale.PercentPattern = "{n}%"
+	}
+	if locale.CurrencyPattern == "" {
+		locale.CurrencyPattern = "{n} {s}"
+	}
+
+	numberLocales.Lock()
+	defer numberLocales.Unlock()
+	numberLocales.byTag[tag] = locale
+	return nil
+}
+
+// LookupNumberLocale accepts language tags like "sk", "de-AT" or "fr_CA"; the
+// exact tag wins over its base language.
+func LookupNumberLocale(tag string) (NumberLocale, error) {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+
+	numberLocales.RLock()
+	defer numberLocales.RUnlock()
+	if locale, ok := numberLocales.byTag[normalized]; ok {
+		return locale, nil
+	}
+	base, _, _ := strings.Cut(normalized, "-")
+	if locale, ok := numberLocales.byTag[base]; ok {
+		return locale, nil
+	}
+	return NumberLocale{}, fmt.Errorf("unsupported number locale %q", tag)
+}
+
+func MustNumberLocale(tag string) NumberLocale {
+	locale, err := LookupNumberLocale(tag)
+	if err != nil {
+		panic(err)
+	}
+	return locale
+}
+
+func (l NumberLocale) FormatDecimal(d Decimal) string {
+	return l.negative(d.Sign() < 0, l.digits(d))
+}
+
+func (l NumberLocale) FormatRat(r *big.Rat, scale int, mode RoundingMode) (string, error) {
+	d, err := DecimalFromRat(r, scale, mode)
+	if err != nil {
+		return "", err
+	}
+	return l.FormatDecimal(d), nil
+}
+
+// FormatPercent renders a fraction as percent, so 0.125 becomes "12.5%".
+func (l NumberLocale) FormatPercent(fraction Decimal, scale int, mode RoundingMode) (string, error) {
+	percent, err := fraction.MulRound(NewDecimal(100, 0), scale, mode)
+	if err != nil {
+		return "", err
+	}
+	v := strings.ReplaceAll(l.PercentPattern, "{n}", l.digits(percent))
+	return l.negative(percent.Sign() < 0, v), nil
+}
+
+func (l NumberLocale) FormatMoney(m Money) string {
+	v := strings.NewReplacer("{n}", l.digits(m.Amount()), "{s}", m.Currency().Symbol).Replace(l.CurrencyPattern)
+	return l.negative(m.Sign() < 0, v)
+}
+
+func (l NumberLocale) negative(negative bool, v string) string {
+	if !negative {
+		return v
+	}
+	return strings.ReplaceAll(l.NegativePattern, "{v}", v)
+}
+
+// digits renders the absolute value of d with grouping and decimal separator.
+func (l NumberLocale) digits(d Decimal) string {
+	intPart, fracPart, _ := strings.Cut(d.Abs().String(), ".")
+
+	var sb strings.Builder
+	for i, c := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			sb.WriteString(l.GroupSep)
+		}
+		sb.WriteRune(c)
+	}
+	if fracPart != "" {
+		sb.WriteString(l.DecimalSep)
+		sb.WriteString(fracPart)
+	}
+	return sb.String()
+}
+
+// ParseDecimal parses user input like "1.234,5" (de) or "-1 234,50" (sk).
+// Grouping is optional but, when present, must split the integer part into
+// groups of three digits. Any space is accepted where the locale groups with
+// a space.
+func (l NumberLocale) ParseDecimal(s string) (Decimal, error) {
+	original := s
+	s = strings.TrimSpace(s)
+
+	negative := false
+	for _, minus := range []string{"-", "\u2212"} {
+		if strings.HasPrefix(s, minus) {
+			negative = true
+			s = strings.TrimSpace(strings.TrimPrefix(s, minus))
+			break
+		}
+	}
+
+	intPart, fracPart, hasFrac := strings.Cut(s, l.DecimalSep)
+	if hasFrac && fracPart == "" || strings.Contains(fracPart, l.DecimalSep) {
+		return Decimal{}, fmt.Errorf("invalid number %q", original)
+	}
+
+	groups := l.splitGroups(intPart)
+	if len(groups) == 0 {
+		return Decimal{}, fmt.Errorf("invalid number %q", original)
+	}
+	for i, group := range groups {
+		if group == "" || !isDigits(group) || i > 0 && len(group) != 3 || i == 0 && len(groups) > 1 && len(group) > 3 {
+			return Decimal{}, fmt.Errorf("invalid number %q", original)
+		}
+	}
+	if !isDigits(fracPart) {
+		return Decimal{}, fmt.Errorf("invalid number %q", original)
+	}
+
+	plain := strings.Join(groups, "")
+	if hasFrac {
+		plain += "." + fracPart
+	}
+	if negative {
+		plain = "-" + plain
+	}
+	d, err := ParseDecimal(plain)
+	if err != nil {
+		return Decimal{}, fmt.Errorf("invalid number %q", original)
+	}
+	return d, nil
+}
+
+func (l NumberLocale) splitGroups(s string) []string {
+	if strings.TrimSpace(l.GroupSep) == "" {
+		return strings.FieldsFunc(s, unicode.IsSpace)
+	}
+	return strings.Split(s, l.GroupSep)
+}
+
+// ParseMoney parses a localized amount with an optional currency symbol or code.
+func (l NumberLocale) ParseMoney(s, code string) (Money, error) {
+	currency, err := LookupCurrency(code)
+	if err != nil {
+		return Money{}, err
+	}
+	s = strings.TrimSpace(strings.NewReplacer(currency.Symbol, "", currency.Code, "").Replace(s))
+	d, err := l.ParseDecimal(s)
+	if err != nil {
+		return Money{}, err
+	}
+	return NewMoney(d, currency.Code)
+}
@@ -0,0 +1,131 @@
+package common
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupNumberLocale(t *testing.T) {
+	for _, tag := range []string{"sk", "SK", "sk-SK", "sk_SK"} {
+		locale, err := LookupNumberLocale(tag)
+		require.NoError(t, err, tag)
+		assert.Equal(t, "sk", locale.Tag)
+	}
+
+	_, err := LookupNumberLocale("xx")
+	assert.Error(t, err)
+}
+
+func TestRegisterNumberLocale(t *testing.T) {
+	require.NoError(t, RegisterNumberLocale(NumberLocale{Tag: "de-CH", DecimalSep: ".", GroupSep: "'"}))
+	locale := MustNumberLocale("de_ch")
+	assert.Equal(t, "1'234.50", locale.FormatDecimal(MustParseDecimal("1234.50")))
+	assert.Equal(t, "1.234,50", MustNumberLocale("de-DE").FormatDecimal(MustParseDecimal("1234.50")))
+
+	assert.Error(t, RegisterNumberLocale(NumberLocale{Tag: "xx", DecimalSep: ",", GroupSep: ","}))
+}
+
+func TestNumberLocale_FormatDecimal(t *testing.T) {
+	tests := []struct {
+		tag  string
+		in   string
+		want string
+	}{
+		{"en", "1234567.891", "1,234,567.891"},
+		{"en", "-1234.5", "-1,234.5"},
+		{"en", "999", "999"},
+		{"de", "1234567.89", "1.234.567,89"},
+		{"sk", "1234.50", "1\u00a0234,50"},
+		{"cs", "-0.5", "-0,5"},
+		{"fr", "1234567", "1\u202f234\u202f567"},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.want, MustNumberLocale(tc.tag).FormatDecimal(MustParseDecimal(tc.in)), tc.tag+" "+tc.in)
+	}
+}
+
+func TestNumberLocale_FormatRat(t *testing.T) {
+	got, err := MustNumberLocale("de").FormatRat(big.NewRat(10000, 3), 2, RoundHalfUp)
+	require.NoError(t, err)
+	assert.Equal(t, "3.333,33", got)
+}
+
+func TestNumberLocale_FormatPercent(t *testing.T) {
+	got, err := MustNumberLocale("en").FormatPercent(MustParseDecimal("0.125"), 1, RoundHalfUp)
+	require.NoError(t, err)
+	assert.Equal(t, "12.5%", got)
+
+	got, err = MustNumberLocale("sk").FormatPercent(MustParseDecimal("-0.2"), 0, RoundHalfUp)
+	require.NoError(t, err)
+	assert.Equal(t, "-20\u00a0%", got)
+}
+
+func TestNumberLocale_FormatMoney(t *testing.T) {
+	amount := MustParseMoney("1234.5", "EUR")
+
+	assert.Equal(t, "€1,234.50", MustNumberLocale("en").FormatMoney(amount))
+	assert.Equal(t, "1.234,50\u00a0€", MustNumberLocale("de").FormatMoney(amount))
+	assert.Equal(t, "1\u00a0234,50\u00a0€", MustNumberLocale("sk").FormatMoney(amount))
+	assert.Equal(t, "1\u202f234,50\u00a0€", MustNumberLocale("fr").FormatMoney(amount))
+	assert.Equal(t, "-1\u00a0234,50\u00a0Kč", MustNumberLocale("cs").FormatMoney(MustParseMoney("-1234.5", "CZK")))
+	assert.Equal(t, "-$0.99", MustNumberLocale("en").FormatMoney(MustParseMoney("-0.99", "USD")))
+}
+
+func TestNumberLocale_ParseDecimal(t *testing.T) {
+	tests := []struct {
+		tag  string
+		in   string
+		want string
+	}{
+		{"de", "1.234,5", "1234.5"},
+		{"de", "1234,5", "1234.5"},
+		{"de", "-12", "-12"},
+		{"en", "1,234,567.25", "1234567.25"},
+		{"sk", "1 234,50", "1234.50"},
+		{"sk", "1\u00a0234,50", "1234.50"},
+		{"fr", "\u22121\u202f000", "-1000"},
+		{"cs", " 0,75 ", "0.75"},
+	}
+
+	for _, tc := range tests {
+		got, err := MustNumberLocale(tc.tag).ParseDecimal(tc.in)
+		require.NoError(t, err, tc.tag+" "+tc.in)
+		assert.Equal(t, tc.want, got.String(), tc.tag+" "+tc.in)
+	}
+
+	invalid := []struct {
+		tag string
+		in  string
+	}{
+		{"de", "1.5"},
+		{"de", "1,2,3"},
+		{"de", "1234.567"},
+		{"de", ",5"},
+		{"en", "1,23.4"},
+		{"en", "12."},
+		{"sk", "1 23,4"},
+		{"sk", "abc"},
+		{"en", ""},
+	}
+	for _, tc := range invalid {
+		_, err := MustNumberLocale(tc.tag).ParseDecimal(tc.in)
+		assert.Error(t, err, tc.tag+" "+tc.in)
+	}
+}
+
+func TestNumberLocale_ParseMoney(t *testing.T) {
+	m, err := MustNumberLocale("sk").ParseMoney("1 234,50 €", "EUR")
+	require.NoError(t, err)
+	assert.Equal(t, "1234.50 EUR", m.String())
+
+	m, err = MustNumberLocale("en").ParseMoney("USD 12.5", "USD")
+	require.NoError(t, err)
+	assert.Equal(t, "12.50 USD", m.String())
+
+	_, err = MustNumberLocale("en").ParseMoney("12.555", "USD")
+	assert.Error(t, err)
+}
@@ -0,0 +1,118 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// PageableBinder reads a Pageable from request query parameters.
+type PageableBinder struct {
+	PageParam   string
+	SizeParam   string
+	SortParam   string
+	DefaultSize int32
+	MaxSize     int32
+	DefaultSort string
+}
+
+func NewPageableBinder(defaultSort string) *PageableBinder {
+	return &PageableBinder{
+		PageParam:   "page",
+		SizeParam:   "size",
+		SortParam:   "sort",
+		DefaultSize: 20,
+		MaxSize:     100,
+		DefaultSort: defaultSort,
+	}
+}
+
+// Bind parses ?page=0&size=20&sort=name,asc&sort=id,desc. A missing or zero
+// size means DefaultSize and a size above MaxSize is clamped; malformed values
+// are reported as a 400 ServiceError.
+func (b *PageableBinder) Bind(r *http.Request) (*Pageable, error) {
+	query := r.URL.Query()
+
+	page, err := parsePageableParam(query, b.PageParam, 0)
+	if err != nil {
+		return nil, err
+	}
+	size, err := parsePageableParam(query, b.SizeParam, b.DefaultSize)
+	if err != nil {
+		return nil, err
+	}
+	if size == 0 {
+		size = b.DefaultSize
+	}
+	if b.MaxSize > 0 && size > b.MaxSize {
+		size = b.MaxSize
+	}
+
+	sort, err := ParseSortValues(query[b.SortParam])
+	if err != nil {
+		return nil, NewServiceError(http.StatusBadRequest, "INVALID_PAGEABLE", err.Error())
+	}
+
+	var sortValue string
+	if len(sort) > 0 {
+		sortValue = sort.String()
+	}
+	return NewPageable(page, size, sortValue, b.DefaultSort), nil
+}
+
+func parsePageableParam(query url.Values, name string, def int32) (int32, error) {
+	value := strings.TrimSpace(query.Get(name))
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || n < 0 {
+		return 0, NewServiceError(http.StatusBadRequest, "INVALID_PAGEABLE", fmt.Sprintf("invalid %s parameter %q", name, value))
+	}
+	return int32(n), nil
+}
+
+// WritePage writes page as JSON with an X-Total-Count header and RFC 8288 Link
+// header pointing to the first, previous, next and last pages. The links keep
+// the request's other query parameters; binder supplies the parameter names
+// and may be nil for the defaults.
+func WritePage[T any](w http.ResponseWriter, r *http.Request, binder *PageableBinder, page *Page[T]) error {
+	if binder == nil {
+		binder = NewPageableBinder("")
+	}
+
+	if links := pageLinks(r, binder, page); len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+	w.Header().Set("X-Total-Count", strconv.FormatInt(page.TotalElements, 10))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(page)
+}
+
+func pageLinks[T any](r *http.Request, binder *PageableBinder, page *Page[T]) []string {
+	if page.Pageable == nil || page.TotalPages == 0 {
+		return nil
+	}
+
+	link := func(number int32, rel string) string {
+		query := r.URL.Query()
+		query.Set(binder.PageParam, strconv.Itoa(int(number)))
+		query.Set(binder.SizeParam, strconv.Itoa(int(page.Pageable.Size)))
+		target := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+		return fmt.Sprintf("<%s>; rel=%q", target.String(), rel)
+	}
+
+	current := page.Pageable.Page
+	links := []string{link(0, "first")}
+	if current > 0 {
+		links = append(links, link(min(current, page.TotalPages)-1, "prev"))
+	}
+	if current < page.TotalPages-1 {
+		links = append(links, link(current+1, "next"))
+	}
+	return append(links, link(page.TotalPages-1, "last"))
+}
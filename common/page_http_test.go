@@ -0,0 +1,76 @@
+package common
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageableBinder_Bind(t *testing.T) {
+	binder := NewPageableBinder("id,asc")
+
+	pageable, err := binder.Bind(httptest.NewRequest(http.MethodGet, "/users", nil))
+	require.NoError(t, err)
+	assert.Equal(t, &Pageable{Page: 0, Size: 20, Sort: "id,asc"}, pageable)
+
+	pageable, err = binder.Bind(httptest.NewRequest(http.MethodGet, "/users?page=2&size=500&sort=name,desc&sort=id", nil))
+	require.NoError(t, err)
+	assert.Equal(t, &Pageable{Page: 2, Size: 100, Sort: "name,desc;id,asc"}, pageable)
+
+	binder.DefaultSize = 50
+	pageable, err = binder.Bind(httptest.NewRequest(http.MethodGet, "/users?size=0", nil))
+	require.NoError(t, err)
+	assert.Equal(t, int32(50), pageable.Size)
+
+	for _, target := range []string{"/users?page=x", "/users?size=-1", "/users?sort=name%27"} {
+		_, err = binder.Bind(httptest.NewRequest(http.MethodGet, target, nil))
+		assert.True(t, IsCode(err, "INVALID_PAGEABLE"), target)
+	}
+}
+
+func TestPageableBinder_CustomParams(t *testing.T) {
+	binder := &PageableBinder{PageParam: "p", SizeParam: "limit", SortParam: "order", MaxSize: 50}
+
+	pageable, err := binder.Bind(httptest.NewRequest(http.MethodGet, "/users?p=1&limit=5&order=name", nil))
+	require.NoError(t, err)
+	assert.Equal(t, &Pageable{Page: 1, Size: 5, Sort: "name,asc"}, pageable)
+}
+
+func TestWritePage(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/users?page=1&size=2&q=jo", nil)
+	pageable, err := NewPageableBinder("id").Bind(r)
+	require.NoError(t, err)
+	page := NewPage(pageable, 5, []string{"c", "d"})
+
+	w := httptest.NewRecorder()
+	require.NoError(t, WritePage(w, r, nil, page))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, "5", w.Header().Get("X-Total-Count"))
+	assert.Equal(t,
+		`</users?page=0&q=jo&size=2>; rel="first", `+
+			`</users?page=0&q=jo&size=2>; rel="prev", `+
+			`</users?page=2&q=jo&size=2>; rel="next", `+
+			`</users?page=2&q=jo&size=2>; rel="last"`,
+		w.Header().Get("Link"))
+
+	var body Page[string]
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, []string{"c", "d"}, body.Content)
+	assert.Equal(t, int32(3), body.TotalPages)
+}
+
+func TestWritePage_Empty(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/users", nil)
+	page := NewPage(NewPageable(0, 20, "", "id"), 0, []string{})
+
+	w := httptest.NewRecorder()
+	require.NoError(t, WritePage(w, r, nil, page))
+	assert.Empty(t, w.Header().Get("Link"))
+	assert.Equal(t, "0", w.Header().Get("X-Total-Count"))
+}
@@ -0,0 +1,121 @@
+package common
+
+import (
+	"context"
+	"iter"
+	"sync"
+)
+
+// MapPage converts the content of page and keeps its metadata.
+func MapPage[T, R any](page *Page[T], fn func(T) R) *Page[R] {
+	content := make([]R, len(page.Content))
+	for i, item := range page.Content {
+		content[i] = fn(item)
+	}
+	return withPageContent(page, content)
+}
+
+// MapPageErr is MapPage with a fallible mapping, it stops at the first error.
+func MapPageErr[T, R any](page *Page[T], fn func(T) (R, error)) (*Page[R], error) {
+	content := make([]R, len(page.Content))
+	for i, item := range page.Content {
+		mapped, err := fn(item)
+		if err != nil {
+			return nil, err
+		}
+		content[i] = mapped
+	}
+	return withPageContent(page, content), nil
+}
+
+// MapPageParallel maps the content with up to workers goroutines and keeps the
+// order. The first error cancels the context passed to the remaining calls.
+func MapPageParallel[T, R any](ctx context.Context, page *Page[T], workers int, fn func(context.Context, T) (R, error)) (*Page[R], error) {
+	if workers <= 0 {
+		workers = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	content := make([]R, len(page.Content))
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	slots := make(chan struct{}, workers)
+	for i, item := range page.Content {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if err := ctx.Err(); err != nil {
+			fail(err)
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			mapped, err := fn(ctx, item)
+			if err != nil {
+				fail(err)
+				return
+			}
+			content[i] = mapped
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return withPageContent(page, content), nil
+}
+
+func withPageContent[T, R any](page *Page[T], content []R) *Page[R] {
+	return &Page[R]{
+		Pageable:      page.Pageable,
+		TotalElements: page.TotalElements,
+		TotalPages:    page.TotalPages,
+		First:         page.First,
+		Last:          page.Last,
+		Content:       content,
+		Empty:         len(content) == 0,
+	}
+}
+
+// AllPages fetches pages starting at pageable until the last one. It yields
+// the error and stops when fetch fails or ctx is cancelled.
+func AllPages[T any](ctx context.Context, pageable *Pageable, fetch func(context.Context, *Pageable) (*Page[T], error)) iter.Seq2[*Page[T], error] {
+	return func(yield func(*Page[T], error) bool) {
+		next := *pageable
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+
+			current := next
+			page, err := fetch(ctx, &current)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(page, nil) || page.Last || page.Empty {
+				return
+			}
+			next.Page++
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapPage(t *testing.T) {
+	page := NewPage(NewPageable(1, 2, "", "id"), 5, []int{3, 4})
+
+	mapped := MapPage(page, strconv.Itoa)
+	assert.Equal(t, []string{"3", "4"}, mapped.Content)
+	assert.Same(t, page.Pageable, mapped.Pageable)
+	assert.Equal(t, page.TotalElements, mapped.TotalElements)
+	assert.Equal(t, page.TotalPages, mapped.TotalPages)
+	assert.Equal(t, page.First, mapped.First)
+	assert.Equal(t, page.Last, mapped.Last)
+	assert.False(t, mapped.Empty)
+}
+
+func TestMapPageErr(t *testing.T) {
+	page := NewPage(NewPageable(0, 3, "", "id"), 3, []string{"1", "2", "x"})
+
+	_, err := MapPageErr(page, strconv.Atoi)
+	assert.Error(t, err)
+
+	page.Content = page.Content[:2]
+	mapped, err := MapPageErr(page, strconv.Atoi)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, mapped.Content)
+}
+
+func TestMapPageParallel(t *testing.T) {
+	content := make([]int, 50)
+	for i := range content {
+		content[i] = i
+	}
+	page := NewPage(NewPageable(0, 50, "", "id"), 50, content)
+
+	var running, maxRunning atomic.Int32
+	mapped, err := MapPageParallel(context.Background(), page, 4, func(_ context.Context, v int) (int, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			m := maxRunning.Load()
+			if n <= m || maxRunning.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		return v * 2, nil
+	})
+	require.NoError(t, err)
+	for i, v := range mapped.Content {
+		assert.Equal(t, i*2, v)
+	}
+	assert.LessOrEqual(t, maxRunning.Load(), int32(4))
+
+	boom := errors.New("boom")
+	_, err = MapPageParallel(context.Background(), page, 4, func(ctx context.Context, v int) (int, error) {
+		if v == 10 {
+			return 0, boom
+		}
+		return v, ctx.Err()
+	})
+	assert.True(t, errors.Is(err, boom) || errors.Is(err, context.Canceled))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = MapPageParallel(ctx, page, 4, func(_ context.Context, v int) (int, error) { return v, nil })
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func pagedTestFetch(total int) func(context.Context, *Pageable) (*Page[int], error) {
+	return func(_ context.Context, pageable *Pageable) (*Page[int], error) {
+		var content []int
+		for i := int(pageable.Offset()); i < total && len(content) < int(pageable.Size); i++ {
+			content = append(content, i)
+		}
+		return NewPage(pageable, int64(total), content), nil
+	}
+}
+
+func TestAllPages(t *testing.T) {
+	var numbers []int32
+	var items []int
+	for page, err := range AllPages(context.Background(), NewPageable(0, 2, "", "id"), pagedTestFetch(5)) {
+		require.NoError(t, err)
+		numbers = append(numbers, page.Pageable.Page)
+		items = append(items, page.Content...)
+	}
+	assert.Equal(t, []int32{0, 1, 2}, numbers)
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, items)
+
+	count := 0
+	for range AllPages(context.Background(), NewPageable(0, 2, "", "id"), pagedTestFetch(0)) {
+		count++
+	}
+	assert.Equal(t, 1, count)
+}
+
+func TestAllPages_Stops(t *testing.T) {
+	boom := errors.New("boom")
+	fetch := func(ctx context.Context, pageable *Pageable) (*Page[int], error) {
+		if pageable.Page == 1 {
+			return nil, boom
+		}
+		return pagedTestFetch(10)(ctx, pageable)
+	}
+
+	var errs []error
+	for _, err := range AllPages(context.Background(), NewPageable(0, 2, "", "id"), fetch) {
+		errs = append(errs, err)
+	}
+	assert.Equal(t, []error{nil, boom}, errs)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pages := 0
+	for page, err := range AllPages(ctx, NewPageable(0, 2, "", "id"), pagedTestFetch(10)) {
+		if err != nil {
+			assert.ErrorIs(t, err, context.Canceled)
+			break
+		}
+		pages++
+		if page.Pageable.Page == 1 {
+			cancel()
+		}
+	}
+	assert.Equal(t, 2, pages)
+
+	pages = 0
+	for range AllPages(context.Background(), NewPageable(0, 2, "", "id"), pagedTestFetch(10)) {
+		pages++
+		break
+	}
+	assert.Equal(t, 1, pages)
+}
@@ -0,0 +1,198 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type SortDirection int
+
+const (
+	SortAsc SortDirection = iota
+	SortDesc
+)
+
+type NullHandling int
+
+const (
+	NullsNative NullHandling = iota
+	NullsFirst
+	NullsLast
+)
+
+type Order struct {
+	Property  string
+	Direction SortDirection
+	Nulls     NullHandling
+}
+
+type Sort []Order
+
+// ParseSort parses "name,asc;createdAt,desc". Each ';' separated segment uses
+// the Spring Data form "property[,property...][,asc|desc][,nullsFirst|nullsLast]";
+// spaces work as well as commas, so "id ASC" is accepted too.
+func ParseSort(value string) (Sort, error) {
+	return ParseSortValues(strings.Split(value, ";"))
+}
+
+// ParseSortValues parses repeated sort parameters, e.g. the values of
+// ?sort=name,asc&sort=createdAt,desc.
+func ParseSortValues(values []string) (Sort, error) {
+	var result Sort
+	for _, value := range values {
+		orders, err := parseSortSegment(value)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, orders...)
+	}
+	return result, nil
+}
+
+func parseSortSegment(segment string) ([]Order, error) {
+	tokens := strings.FieldsFunc(segment, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	// Trailing tokens are modifiers, e.g. "name,desc,nullsLast". Each kind may
+	// be given once and at least one property must precede them.
+	direction := SortAsc
+	nulls := NullsNative
+	hasDirection, hasNulls := false, false
+modifiers:
+	for len(tokens) > 0 {
+		modifier := strings.ToLower(tokens[len(tokens)-1])
+		switch modifier {
+		case "asc", "desc":
+			if hasDirection {
+				return nil, fmt.Errorf("sort %q has more than one direction", segment)
+			}
+			hasDirection = true
+			direction = SortAsc
+			if modifier == "desc" {
+				direction = SortDesc
+			}
+		case "nullsfirst", "nulls_first", "nullslast", "nulls_last":
+			if hasNulls {
+				return nil, fmt.Errorf("sort %q has more than one null handling", segment)
+			}
+			hasNulls = true
+			nulls = NullsFirst
+			if modifier == "nullslast" || modifier == "nulls_last" {
+				nulls = NullsLast
+			}
+		default:
+			break modifiers
+		}
+		tokens = tokens[:len(tokens)-1]
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("sort %q has no property", segment)
+	}
+
+	orders := make([]Order, 0, len(tokens))
+	for _, property := range tokens {
+		if !sortPropertyValid(property) {
+			return nil, fmt.Errorf("invalid sort property %q", property)
+		}
+		orders = append(orders, Order{Property: property, Direction: direction, Nulls: nulls})
+	}
+	return orders, nil
+}
+
+func sortPropertyValid(property string) bool {
+	for _, r := range property {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '.' {
+			return false
+		}
+	}
+	return property != ""
+}
+
+func (s Sort) String() string {
+	parts := make([]string, len(s))
+	for i, order := range s {
+		part := order.Property
+		if order.Direction == SortDesc {
+			part += ",desc"
+		} else {
+			part += ",asc"
+		}
+		switch order.Nulls {
+		case NullsFirst:
+			part += ",nullsFirst"
+		case NullsLast:
+			part += ",nullsLast"
+		}
+		parts[i] = part
+	}
+	return strings.Join(parts, ";")
+}
+
+// OrderBy renders the sort as an ORDER BY list. columns whitelists the
+// sortable properties and maps them to column names; any other property is
+// rejected.
+func (s Sort) OrderBy(columns map[string]string) (string, error) {
+	parts := make([]string, len(s))
+	for i, order := range s {
+		column, err := sortColumn(order.Property, columns)
+		if err != nil {
+			return "", err
+		}
+
+		part := column
+		if order.Direction == SortDesc {
+			part += " DESC"
+		} else {
+			part += " ASC"
+		}
+		switch order.Nulls {
+		case NullsFirst:
+			part += " NULLS FIRST"
+		case NullsLast:
+			part += " NULLS LAST"
+		}
+		parts[i] = part
+	}
+	return strings.Join(parts, ", "), nil
+}
+
+// KeysetColumns maps the sort to columns for CursorPageable.
+func (s Sort) KeysetColumns(columns map[string]string) ([]KeysetColumn, error) {
+	result := make([]KeysetColumn, len(s))
+	for i, order := range s {
+		column, err := sortColumn(order.Property, columns)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = KeysetColumn{Column: column, Desc: order.Direction == SortDesc}
+	}
+	return result, nil
+}
+
+func sortColumn(property string, columns map[string]string) (string, error) {
+	column, ok := columns[property]
+	if !ok {
+		return "", fmt.Errorf("sort property %q not allowed", property)
+	}
+	if !sqlIdentifierRegExp.MatchString(column) {
+		return "", fmt.Errorf("invalid sort column %q", column)
+	}
+	return column, nil
+}
+
+func (p *Pageable) ParseSort() (Sort, error) {
+	return ParseSort(p.Sort)
+}
+
+func (p *Pageable) OrderBy(columns map[string]string) (string, error) {
+	sort, err := p.ParseSort()
+	if err != nil {
+		return "", err
+	}
+	return sort.OrderBy(columns)
+}
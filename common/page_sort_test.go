@@ -0,0 +1,96 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var sortTestColumns = map[string]string{
+	"id":        "id",
+	"name":      "u.name",
+	"createdAt": "created_at",
+}
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		value string
+		want  Sort
+	}{
+		{"", nil},
+		{"name", Sort{{Property: "name"}}},
+		{"id ASC", Sort{{Property: "id"}}},
+		{"name DESC", Sort{{Property: "name", Direction: SortDesc}}},
+		{"name,asc;createdAt,desc", Sort{{Property: "name"}, {Property: "createdAt", Direction: SortDesc}}},
+		{"name,createdAt,desc", Sort{{Property: "name", Direction: SortDesc}, {Property: "createdAt", Direction: SortDesc}}},
+		{"createdAt,desc,nullsLast", Sort{{Property: "createdAt", Direction: SortDesc, Nulls: NullsLast}}},
+		{" name , NULLS_FIRST ; ", Sort{{Property: "name", Nulls: NullsFirst}}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.value, func(t *testing.T) {
+			sort, err := ParseSort(tc.value)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, sort)
+		})
+	}
+
+	_, err := ParseSort("name;drop table x--")
+	assert.Error(t, err)
+	_, err = ParseSort("name'),asc")
+	assert.Error(t, err)
+
+	for _, value := range []string{"desc", "asc,nullsLast", "name;desc", "name,asc,desc", "name,desc,desc", "name,nullsFirst,nulls_last"} {
+		_, err = ParseSort(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestParseSortValues(t *testing.T) {
+	sort, err := ParseSortValues([]string{"name,asc", "createdAt,desc", ""})
+	require.NoError(t, err)
+	assert.Equal(t, Sort{{Property: "name"}, {Property: "createdAt", Direction: SortDesc}}, sort)
+	assert.Equal(t, "name,asc;createdAt,desc", sort.String())
+}
+
+func TestSort_OrderBy(t *testing.T) {
+	sort, err := ParseSort("name;createdAt,desc,nullsLast;id,nullsFirst")
+	require.NoError(t, err)
+
+	orderBy, err := sort.OrderBy(sortTestColumns)
+	require.NoError(t, err)
+	assert.Equal(t, "u.name ASC, created_at DESC NULLS LAST, id ASC NULLS FIRST", orderBy)
+
+	_, err = Sort{{Property: "password"}}.OrderBy(sortTestColumns)
+	assert.Error(t, err)
+	_, err = Sort{{Property: "bad"}}.OrderBy(map[string]string{"bad": "x; DROP TABLE y"})
+	assert.Error(t, err)
+
+	orderBy, err = Sort{}.OrderBy(sortTestColumns)
+	require.NoError(t, err)
+	assert.Empty(t, orderBy)
+}
+
+func TestSort_KeysetColumns(t *testing.T) {
+	sort, err := ParseSort("createdAt,desc;id")
+	require.NoError(t, err)
+
+	columns, err := sort.KeysetColumns(sortTestColumns)
+	require.NoError(t, err)
+	assert.Equal(t, []KeysetColumn{{Column: "created_at", Desc: true}, {Column: "id"}}, columns)
+
+	_, err = Sort{{Property: "unknown"}}.KeysetColumns(sortTestColumns)
+	assert.Error(t, err)
+}
+
+func TestPageable_OrderBy(t *testing.T) {
+	pageable := NewPageable(0, 10, "", "id ASC")
+	orderBy, err := pageable.OrderBy(sortTestColumns)
+	require.NoError(t, err)
+	assert.Equal(t, "id ASC", orderBy)
+
+	pageable = NewPageable(0, 10, "id; DELETE FROM users", "id")
+	_, err = pageable.OrderBy(sortTestColumns)
+	assert.Error(t, err)
+}
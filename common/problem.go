@@ -0,0 +1,209 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"maps"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const ProblemContentType = "application/problem+json"
+
+var problemTypeBase = struct {
+	sync.RWMutex
+	value string
+}{}
+
+// SetProblemTypeBase makes problem types resolvable URIs: with base
+// "https://example.com/problems/" the code NOT_FOUND becomes
+// "https://example.com/problems/not-found". An empty base uses "about:blank".
+func SetProblemTypeBase(base string) {
+	problemTypeBase.Lock()
+	defer problemTypeBase.Unlock()
+	problemTypeBase.value = base
+}
+
+func problemType(code string) string {
+	problemTypeBase.RLock()
+	defer problemTypeBase.RUnlock()
+	if problemTypeBase.value == "" || code == "" {
+		return "about:blank"
+	}
+	return problemTypeBase.value + strings.ToLower(strings.ReplaceAll(code, "_", "-"))
+}
+
+// Problem is an RFC 9457 problem details document. Code and Errors are
+// extension members; any other extension is kept in Extensions.
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Code       string
+	Errors     []FieldError
+	Extensions map[string]any
+}
+
+// problemDocument is the wire form of the standard members.
+type problemDocument struct {
+	Type     string       `json:"type,omitempty"`
+	Title    string       `json:"title,omitempty"`
+	Status   int          `json:"status,omitempty"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     string       `json:"code,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+var problemMembers = map[string]bool{
+	"type": true, "title": true, "status": true, "detail": true, "instance": true, "code": true, "errors": true,
+}
+
+// NewProblem maps err to a problem. Errors that do not wrap a *ServiceError
+// become a 500 without details so internals do not leak to clients.
+func NewProblem(err error) *Problem {
+	var se *ServiceError
+	if !errors.As(err, &se) {
+		se = NewServiceError(http.StatusInternalServerError, "INTERNAL_ERROR", http.StatusText(http.StatusInternalServerError))
+	}
+
+	status := se.Status
+	if status < 400 || status > 599 {
+		status = http.StatusInternalServerError
+	}
+	return &Problem{
+		Type:       problemType(se.Code),
+		Title:      http.StatusText(status),
+		Status:     status,
+		Detail:     se.Message,
+		Code:       se.Code,
+		Errors:     se.FieldErrors,
+		Extensions: maps.Clone(se.Extensions),
+	}
+}
+
+func (p *Problem) ServiceError() *ServiceError {
+	message := p.Detail
+	if message == "" {
+		message = p.Title
+	}
+	return &ServiceError{
+		Status:      p.Status,
+		Code:        p.Code,
+		Message:     message,
+		FieldErrors: p.Errors,
+		Extensions:  maps.Clone(p.Extensions),
+	}
+}
+
+func (p Problem) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(problemDocument{
+		Type:     p.Type,
+		Title:    p.Title,
+		Status:   p.Status,
+		Detail:   p.Detail,
+		Instance: p.Instance,
+		Code:     p.Code,
+		Errors:   p.Errors,
+	})
+	if err != nil || len(p.Extensions) == 0 {
+		return data, err
+	}
+
+	members := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+	for key, value := range p.Extensions {
+		if problemMembers[key] {
+			continue
+		}
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		members[key] = raw
+	}
+	return json.Marshal(members)
+}
+
+func (p *Problem) UnmarshalJSON(data []byte) error {
+	var document problemDocument
+	if err := json.Unmarshal(data, &document); err != nil {
+		return err
+	}
+	var members map[string]any
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+
+	*p = Problem{
+		Type:     document.Type,
+		Title:    document.Title,
+		Status:   document.Status,
+		Detail:   document.Detail,
+		Instance: document.Instance,
+		Code:     document.Code,
+		Errors:   document.Errors,
+	}
+	for key, value := range members {
+		if problemMembers[key] {
+			continue
+		}
+		if p.Extensions == nil {
+			p.Extensions = make(map[string]any)
+		}
+		p.Extensions[key] = value
+	}
+	return nil
+}
+
+func (p *Problem) Write(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(p.Status)
+	return json.NewEncoder(w).Encode(p)
+}
+
+// WriteProblem writes err as application/problem+json, see NewProblem.
+func WriteProblem(w http.ResponseWriter, err error) error {
+	return NewProblem(err).Write(w)
+}
+
+// ReadProblem returns nil for a successful response, otherwise the
+// *ServiceError described by its problem (or plain JSON) body. Other bodies
+// are used as the message. It consumes the body but does not close it.
+func ReadProblem(resp *http.Response) error {
+	if resp.StatusCode < 400 {
+		return nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == ProblemContentType || mediaType == "application/json" {
+		var problem Problem
+		if err := json.Unmarshal(body, &problem); err == nil {
+			if problem.Status == 0 {
+				problem.Status = resp.StatusCode
+			}
+			if problem.Title == "" {
+				problem.Title = http.StatusText(resp.StatusCode)
+			}
+			return problem.ServiceError()
+		}
+	}
+
+	message := strings.TrimSpace(string(body))
+	if message == "" {
+		message = http.StatusText(resp.StatusCode)
+	}
+	return NewServiceError(resp.StatusCode, "", message)
+}
@@ -0,0 +1,121 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteProblem(t *testing.T) {
+	SetProblemTypeBase("https://example.com/problems/")
+	defer SetProblemTypeBase("")
+
+	se := NewServiceError(http.StatusUnprocessableEntity, "INVALID_USER", "user is invalid")
+	se.FieldErrors = []FieldError{{Field: "email", Code: "EMAIL", Message: "must be an email"}}
+	se.Extensions = map[string]any{"traceId": "abc", "status": 1}
+
+	w := httptest.NewRecorder()
+	require.NoError(t, WriteProblem(w, fmt.Errorf("create user: %w", se)))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"type": "https://example.com/problems/invalid-user",
+		"title": "Unprocessable Entity",
+		"status": 422,
+		"detail": "user is invalid",
+		"code": "INVALID_USER",
+		"errors": [{"field": "email", "code": "EMAIL", "message": "must be an email"}],
+		"traceId": "abc"
+	}`, w.Body.String())
+}
+
+func TestWriteProblem_InternalError(t *testing.T) {
+	w := httptest.NewRecorder()
+	require.NoError(t, WriteProblem(w, errors.New("pq: connection refused")))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "connection refused")
+
+	var problem Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, "about:blank", problem.Type)
+	assert.Equal(t, "INTERNAL_ERROR", problem.Code)
+}
+
+func TestProblem_RoundTrip(t *testing.T) {
+	problem := &Problem{
+		Type:       "about:blank",
+		Title:      "Conflict",
+		Status:     http.StatusConflict,
+		Detail:     "already exists",
+		Instance:   "/users/1",
+		Code:       "USER_EXISTS",
+		Extensions: map[string]any{"id": "1"},
+	}
+	data, err := json.Marshal(problem)
+	require.NoError(t, err)
+
+	var decoded Problem
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, *problem, decoded)
+}
+
+func TestReadProblem(t *testing.T) {
+	se := NewServiceError(http.StatusNotFound, "USER_NOT_FOUND", "user not found")
+	se.FieldErrors = []FieldError{{Field: "id", Code: "UNKNOWN", Message: "no such id"}}
+	se.Extensions = map[string]any{"id": "42"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/problem":
+			_ = WriteProblem(w, se)
+		case "/text":
+			http.Error(w, "boom", http.StatusBadGateway)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	get := func(path string) error {
+		resp, err := http.Get(server.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return ReadProblem(resp)
+	}
+
+	err := get("/problem")
+	var read *ServiceError
+	require.ErrorAs(t, err, &read)
+	assert.Equal(t, se, read)
+	assert.True(t, IsCode(err, "USER_NOT_FOUND"))
+
+	err = get("/text")
+	require.ErrorAs(t, err, &read)
+	assert.Equal(t, http.StatusBadGateway, read.Status)
+	assert.Equal(t, "boom", read.Message)
+
+	assert.NoError(t, get("/ok"))
+}
+
+func TestReadProblem_MissingStatus(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusForbidden,
+		Header:     http.Header{"Content-Type": {ProblemContentType + "; charset=utf-8"}},
+		Body:       io.NopCloser(strings.NewReader(`{"code":"DENIED"}`)),
+	}
+
+	var read *ServiceError
+	require.ErrorAs(t, ReadProblem(resp), &read)
+	assert.Equal(t, http.StatusForbidden, read.Status)
+	assert.Equal(t, "DENIED", read.Code)
+	assert.Equal(t, "Forbidden", read.Message)
+}
@@ -2,26 +2,15 @@ package common
 
 import (
 	"strings"
-
-	"golang.org/x/text/unicode/norm"
 )
 
+// ToDf removes diacritics, "Łódź" → "Lodz".
 func ToDf(text string) string {
 	if IsBlank(text) {
 		return ""
 	}
 
-	var result strings.Builder
-	for _, r := range text {
-		normalized := norm.NFD.String(string(r))
-		if len(normalized) > 1 {
-			result.WriteRune(rune(normalized[0]))
-		} else {
-			result.WriteRune(r)
-		}
-	}
-
-	return strings.TrimSpace(result.String())
+	return strings.TrimSpace(TextNormalizer{RemoveDiacritics: true}.Normalize(text))
 }
 
 func ToScDf(text string) string {
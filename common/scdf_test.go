@@ -17,6 +17,8 @@ func TestToDf(t *testing.T) {
 			{value: "", expected: ""},
 			{value: "  ", expected: ""},
 			{value: "   ľščťžýáíéňäúô ĽŠČŤŽÝÁÍÉŇÄÚÔ   ", expected: "lsctzyaienauo LSCTZYAIENAUO"},
+			{value: "Łódź Straße Ørsted Đorđe", expected: "Lodz Strasse Orsted Dorde"},
+			{value: "ẞ ṩ", expected: "SS s"},
 		}
 
 		for _, test := range tests {
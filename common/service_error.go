@@ -3,18 +3,110 @@ package common
 import (
 	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime"
+	"sort"
+	"strings"
+	"sync/atomic"
 )
 
+var captureServiceErrorStack atomic.Bool
+
+// CaptureServiceErrorStack turns on recording of the call stack in
+// constructors, off by default since it costs an allocation per error.
+func CaptureServiceErrorStack(enabled bool) {
+	captureServiceErrorStack.Store(enabled)
+}
+
 type ServiceError struct {
-	Status  int
-	Code    string
-	Message string
+	Status      int
+	Code        string
+	Message     string
+	FieldErrors []FieldError
+	Extensions  map[string]any
+	// Cause and Metadata are for logs only, they are never sent to clients.
+	Cause    error
+	Metadata map[string]any
+	stack    []uintptr
+}
+
+// FieldError describes one invalid request field, Field is a path like
+// "items[0].name".
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
 }
 
 func (e *ServiceError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("(%d)[%s] %s: %v", e.Status, e.Code, e.Message, e.Cause)
+	}
 	return fmt.Sprintf("(%d)[%s] %s", e.Status, e.Code, e.Message)
 }
 
+func (e *ServiceError) Unwrap() error {
+	return e.Cause
+}
+
+// WithMetadata adds a key/value pair for logging and returns e.
+func (e *ServiceError) WithMetadata(key string, value any) *ServiceError {
+	if e.Metadata == nil {
+		e.Metadata = make(map[string]any)
+	}
+	e.Metadata[key] = value
+	return e
+}
+
+// Stack returns the frames recorded at construction, empty unless
+// CaptureServiceErrorStack is on.
+func (e *ServiceError) Stack() []runtime.Frame {
+	if len(e.stack) == 0 {
+		return nil
+	}
+	var result []runtime.Frame
+	frames := runtime.CallersFrames(e.stack)
+	for {
+		frame, more := frames.Next()
+		result = append(result, frame)
+		if !more {
+			return result
+		}
+	}
+}
+
+func (e *ServiceError) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.Int("status", e.Status),
+		slog.String("code", e.Code),
+		slog.String("message", e.Message),
+	}
+	if e.Cause != nil {
+		attrs = append(attrs, slog.String("cause", e.Cause.Error()))
+	}
+	if len(e.Metadata) > 0 {
+		keys := make([]string, 0, len(e.Metadata))
+		for key := range e.Metadata {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		metadata := make([]any, len(keys))
+		for i, key := range keys {
+			metadata[i] = slog.Any(key, e.Metadata[key])
+		}
+		attrs = append(attrs, slog.Group("metadata", metadata...))
+	}
+	if frames := e.Stack(); len(frames) > 0 {
+		var sb strings.Builder
+		for _, frame := range frames {
+			fmt.Fprintf(&sb, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		}
+		attrs = append(attrs, slog.String("stack", sb.String()))
+	}
+	return slog.GroupValue(attrs...)
+}
+
 func IsCode(err error, code string) bool {
 	var se *ServiceError
 	if errors.As(err, &se) {
@@ -24,5 +116,40 @@ func IsCode(err error, code string) bool {
 }
 
 func NewServiceError(status int, code string, message string) *ServiceError {
-	return &ServiceError{Status: status, Code: code, Message: message}
+	return newServiceError(status, code, message, nil)
+}
+
+// Wrap returns a ServiceError caused by err, so errors.Is and errors.As still
+// reach err.
+func Wrap(err error, status int, code string, message string) *ServiceError {
+	return newServiceError(status, code, message, err)
+}
+
+func NotFound(code string, message string) *ServiceError {
+	return newServiceError(http.StatusNotFound, code, message, nil)
+}
+
+func Conflict(code string, message string) *ServiceError {
+	return newServiceError(http.StatusConflict, code, message, nil)
+}
+
+func Validation(code string, message string, fieldErrors ...FieldError) *ServiceError {
+	se := newServiceError(http.StatusBadRequest, code, message, nil)
+	se.FieldErrors = fieldErrors
+	return se
+}
+
+func Forbidden(code string, message string) *ServiceError {
+	return newServiceError(http.StatusForbidden, code, message, nil)
+}
+
+// newServiceError must be called directly from the exported constructors so
+// the recorded stack starts at their caller.
+func newServiceError(status int, code string, message string, cause error) *ServiceError {
+	se := &ServiceError{Status: status, Code: code, Message: message, Cause: cause}
+	if captureServiceErrorStack.Load() {
+		pcs := make([]uintptr, 32)
+		se.stack = pcs[:runtime.Callers(3, pcs)]
+	}
+	return se
 }
@@ -1,8 +1,13 @@
 package common
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
 	"testing"
 )
 
@@ -46,3 +51,70 @@ func TestIsCode_NotServiceError(t *testing.T) {
 		t.Errorf("Expected IsCode to return false for non-ServiceError")
 	}
 }
+
+func TestWrap_Unwrap(t *testing.T) {
+	cause := os.ErrNotExist
+	err := fmt.Errorf("load: %w", Wrap(cause, http.StatusNotFound, "FILE_NOT_FOUND", "file not found"))
+
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected errors.Is to reach the cause")
+	}
+	if !IsCode(err, "FILE_NOT_FOUND") {
+		t.Errorf("Expected IsCode to match the wrapping ServiceError")
+	}
+
+	expected := "load: (404)[FILE_NOT_FOUND] file not found: file does not exist"
+	if err.Error() != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, err.Error())
+	}
+}
+
+func TestServiceError_Categories(t *testing.T) {
+	tests := []struct {
+		err    *ServiceError
+		status int
+	}{
+		{NotFound("NOT_FOUND", "missing"), http.StatusNotFound},
+		{Conflict("CONFLICT", "exists"), http.StatusConflict},
+		{Validation("INVALID", "invalid", FieldError{Field: "name", Code: "REQUIRED"}), http.StatusBadRequest},
+		{Forbidden("FORBIDDEN", "denied"), http.StatusForbidden},
+	}
+
+	for _, test := range tests {
+		if test.err.Status != test.status {
+			t.Errorf("%s: expected status %d, got %d", test.err.Code, test.status, test.err.Status)
+		}
+	}
+	if len(tests[2].err.FieldErrors) != 1 {
+		t.Errorf("Expected Validation to keep field errors")
+	}
+}
+
+func TestServiceError_Stack(t *testing.T) {
+	if frames := NotFound("A", "a").Stack(); len(frames) != 0 {
+		t.Errorf("Expected no stack by default, got %d frames", len(frames))
+	}
+
+	CaptureServiceErrorStack(true)
+	defer CaptureServiceErrorStack(false)
+
+	frames := NotFound("A", "a").Stack()
+	if len(frames) == 0 || !strings.HasSuffix(frames[0].Function, "TestServiceError_Stack") {
+		t.Errorf("Expected stack to start at the caller, got %v", frames)
+	}
+}
+
+func TestServiceError_LogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	err := Wrap(errors.New("db down"), http.StatusServiceUnavailable, "DB", "database unavailable").
+		WithMetadata("userId", 42).
+		WithMetadata("table", "users")
+	logger.Error("failed", "error", err)
+
+	expected := `"error":{"status":503,"code":"DB","message":"database unavailable","cause":"db down","metadata":{"table":"users","userId":42}}`
+	if !strings.Contains(buf.String(), expected) {
+		t.Errorf("Expected log to contain %s, got %s", expected, buf.String())
+	}
+}
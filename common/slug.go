@@ -0,0 +1,190 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var slugNormalizer = TextNormalizer{Transliterate: true, RemoveDiacritics: true, FoldCase: true}
+
+// SlugOptions configures slug generation. Separator defaults to "-" and a
+// MaxLength of 0 means no limit. Reserved slugs, e.g. "new" or "edit", are
+// never returned as they are; they get a numeric suffix like taken slugs, or
+// Slugify returns "" when the suffix does not fit in MaxLength.
+type SlugOptions struct {
+	Separator string
+	MaxLength int
+	Reserved  []string
+}
+
+// Slugify turns "Žltý kôň & Co." into "zlty-kon-co" with default options.
+func Slugify(text string) string {
+	return SlugOptions{}.Slugify(text)
+}
+
+func (o SlugOptions) Slugify(text string) string {
+	slug := o.truncate(o.base(text), o.MaxLength)
+	if slices.Contains(o.Reserved, slug) {
+		slug, _ = o.withSuffix(slug, 2)
+	}
+	return slug
+}
+
+// UniqueSlug returns the first of "slug", "slug-2", "slug-3", ... that is not
+// reserved and for which exists reports false.
+func (o SlugOptions) UniqueSlug(ctx context.Context, text string, exists func(ctx context.Context, slug string) (bool, error)) (string, error) {
+	base := o.base(text)
+	if base == "" {
+		return "", errors.New("text has no characters usable in a slug")
+	}
+
+	for n := 1; ; n++ {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		slug, ok := o.withSuffix(base, n)
+		if !ok {
+			return "", fmt.Errorf("no unique slug for %q fits in %d characters", text, o.MaxLength)
+		}
+		if slices.Contains(o.Reserved, slug) {
+			continue
+		}
+		taken, err := exists(ctx, slug)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return slug, nil
+		}
+	}
+}
+
+func (o SlugOptions) separator() string {
+	if o.Separator == "" {
+		return "-"
+	}
+	return o.Separator
+}
+
+func (o SlugOptions) base(text string) string {
+	words := strings.FieldsFunc(slugNormalizer.Normalize(text), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+	return strings.Join(words, o.separator())
+}
+
+// withSuffix appends "-n" for n > 1, shortening the slug to keep MaxLength.
+// It reports false when not even one character fits before the suffix.
+func (o SlugOptions) withSuffix(slug string, n int) (string, bool) {
+	if n <= 1 {
+		return o.truncate(slug, o.MaxLength), true
+	}
+	suffix := o.separator() + strconv.Itoa(n)
+	if o.MaxLength <= 0 {
+		return slug + suffix, true
+	}
+	if o.MaxLength-len(suffix) < 1 {
+		return "", false
+	}
+	return o.truncate(slug, o.MaxLength-len(suffix)) + suffix, true
+}
+
+// truncate cuts at the last separator that fits so words are not split,
+// unless the first word alone is too long.
+func (o SlugOptions) truncate(slug string, maxLength int) string {
+	if maxLength <= 0 || len(slug) <= maxLength {
+		return slug
+	}
+	cut := slug[:maxLength]
+	if strings.HasPrefix(slug[maxLength:], o.separator()) {
+		return cut
+	}
+	if i := strings.LastIndex(cut, o.separator()); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimSuffix(cut, o.separator())
+}
+
+var windowsReservedFilenames = []string{
+	"CON", "PRN", "AUX", "NUL",
+	"COM1", "COM2", "COM3", "COM4", "COM5", "COM6", "COM7", "COM8", "COM9",
+	"LPT1", "LPT2", "LPT3", "LPT4", "LPT5", "LPT6", "LPT7", "LPT8", "LPT9",
+}
+
+// SafeFilename makes name usable on Linux, Windows and macOS: path separators,
+// characters Windows forbids and control characters become "_", leading dots
+// and trailing dots or spaces are removed, Windows device names get a "_"
+// prefix and the result is limited to 255 bytes keeping the extension.
+// Unicode letters are kept (in NFC); see ContentDisposition for headers.
+func SafeFilename(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsSpace(r):
+			return ' '
+		case !unicode.IsPrint(r) || strings.ContainsRune(`<>:"/\|?*`, r):
+			return '_'
+		}
+		return r
+	}, norm.NFC.String(name))
+	name = strings.TrimRight(strings.TrimLeft(strings.TrimSpace(name), "."), ". ")
+
+	if name == "" {
+		return "file"
+	}
+
+	stem, _, _ := strings.Cut(name, ".")
+	if slices.Contains(windowsReservedFilenames, strings.ToUpper(strings.TrimSpace(stem))) {
+		name = "_" + name
+	}
+
+	const maxBytes = 255
+	if len(name) <= maxBytes {
+		return name
+	}
+	ext := ""
+	if i := strings.LastIndex(name, "."); i > 0 && len(name)-i <= 16 {
+		ext = name[i:]
+	}
+	stem = name[:len(name)-len(ext)]
+	limit := maxBytes - len(ext)
+	for limit > 0 && !utf8.RuneStart(stem[limit]) {
+		limit--
+	}
+	return strings.TrimRight(stem[:limit], ". ") + ext
+}
+
+// ContentDisposition renders a Content-Disposition header value, e.g. for
+// disposition "attachment", with an ASCII filename fallback and the UTF-8
+// filename* parameter of RFC 6266.
+func ContentDisposition(disposition string, filename string) string {
+	filename = SafeFilename(filename)
+
+	fallback := strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e || r == '"' || r == '\\' || r == '%' {
+			return '_'
+		}
+		return r
+	}, TextNormalizer{Transliterate: true, RemoveDiacritics: true}.Normalize(filename))
+
+	var encoded strings.Builder
+	for _, b := range []byte(filename) {
+		if b < 0x80 && (b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9' || strings.IndexByte("!#$&+-.^_`|~", b) >= 0) {
+			encoded.WriteByte(b)
+		} else {
+			fmt.Fprintf(&encoded, "%%%02X", b)
+		}
+	}
+
+	if fallback == filename {
+		return fmt.Sprintf(`%s; filename="%s"`, disposition, fallback)
+	}
+	return fmt.Sprintf(`%s; filename="%s"; filename*=UTF-8''%s`, disposition, fallback, encoded.String())
+}
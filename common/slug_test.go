@@ -0,0 +1,120 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"", ""},
+		{"Žltý kôň & Co.", "zlty-kon-co"},
+		{"  Hello,   World!  ", "hello-world"},
+		{"Straße 42", "strasse-42"},
+		{"Война и мир", "voyna-i-mir"},
+		{"---", ""},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, Slugify(test.value), test.value)
+	}
+}
+
+func TestSlugOptions_Slugify(t *testing.T) {
+	options := SlugOptions{Separator: "_", MaxLength: 12, Reserved: []string{"new"}}
+
+	assert.Equal(t, "quick_brown", options.Slugify("Quick brown fox"))
+	assert.Equal(t, "supercalifra", options.Slugify("Supercalifragilistic"))
+	assert.Equal(t, "new_2", options.Slugify("New"))
+	assert.Equal(t, "a_b", SlugOptions{Separator: "_", MaxLength: 3}.Slugify("a b c"))
+	assert.Equal(t, "", SlugOptions{MaxLength: 2, Reserved: []string{"ab"}}.Slugify("ab"))
+	assert.Equal(t, "a-2", SlugOptions{MaxLength: 3, Reserved: []string{"ab"}}.Slugify("ab"))
+}
+
+func TestSlugOptions_UniqueSlug(t *testing.T) {
+	taken := map[string]bool{"my-post": true, "my-post-2": true}
+	exists := func(_ context.Context, slug string) (bool, error) {
+		return taken[slug], nil
+	}
+
+	slug, err := SlugOptions{}.UniqueSlug(context.Background(), "My Post", exists)
+	require.NoError(t, err)
+	assert.Equal(t, "my-post-3", slug)
+
+	slug, err = SlugOptions{MaxLength: 8}.UniqueSlug(context.Background(), "My Post", exists)
+	require.NoError(t, err)
+	assert.Equal(t, "my-2", slug)
+
+	slug, err = SlugOptions{Reserved: []string{"edit"}}.UniqueSlug(context.Background(), "Edit", exists)
+	require.NoError(t, err)
+	assert.Equal(t, "edit-2", slug)
+
+	_, err = SlugOptions{}.UniqueSlug(context.Background(), "!!!", exists)
+	assert.Error(t, err)
+
+	boom := errors.New("boom")
+	_, err = SlugOptions{}.UniqueSlug(context.Background(), "x", func(context.Context, string) (bool, error) {
+		return false, boom
+	})
+	assert.ErrorIs(t, err, boom)
+
+	var tried []string
+	allTaken := func(_ context.Context, slug string) (bool, error) {
+		tried = append(tried, slug)
+		return true, nil
+	}
+	slug, err = SlugOptions{MaxLength: 3}.UniqueSlug(context.Background(), "abc", allTaken)
+	assert.Error(t, err)
+	assert.Empty(t, slug)
+	assert.Equal(t, []string{"abc", "a-2", "a-3", "a-4", "a-5", "a-6", "a-7", "a-8", "a-9"}, tried)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = SlugOptions{}.UniqueSlug(ctx, "x", exists)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestSafeFilename(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"", "file"},
+		{"report.pdf", "report.pdf"},
+		{"../../etc/passwd", "_.._etc_passwd"},
+		{`a<b>c:d"e|f?g*h.txt`, "a_b_c_d_e_f_g_h.txt"},
+		{"  .hidden  ", "hidden"},
+		{"name. . ", "name"},
+		{"CON.txt", "_CON.txt"},
+		{"com1", "_com1"},
+		{"line\nbreak\x00.txt", "line break_.txt"},
+		{"Faktúra č. 1.pdf", "Faktúra č. 1.pdf"},
+		{"Fakturé.pdf", "Fakturé.pdf"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, SafeFilename(test.value), test.value)
+	}
+
+	long := SafeFilename(strings.Repeat("ž", 200) + ".docx")
+	assert.LessOrEqual(t, len(long), 255)
+	assert.True(t, strings.HasSuffix(long, "ž.docx"))
+}
+
+func TestContentDisposition(t *testing.T) {
+	assert.Equal(t, `attachment; filename="report.pdf"`, ContentDisposition("attachment", "report.pdf"))
+	assert.Equal(t,
+		`attachment; filename="Faktura c. 1.pdf"; filename*=UTF-8''Fakt%C3%BAra%20%C4%8D.%201.pdf`,
+		ContentDisposition("attachment", "Faktúra č. 1.pdf"))
+	assert.Equal(t,
+		`inline; filename="_100_ __.txt"; filename*=UTF-8''_100%25%20__.txt`,
+		ContentDisposition("inline", `"100% "".txt`))
+}
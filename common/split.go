@@ -0,0 +1,119 @@
+package common
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
+)
+
+var ErrUnterminatedQuote = errors.New("unterminated quote")
+
+// SplitOptions configures splitting of user-entered lists. Every rune of
+// Separators separates fields. Inside Quote separators are literal and a
+// doubled quote is a quote character, as in CSV. Escape makes the next rune
+// literal anywhere. A zero Quote or Escape disables it.
+type SplitOptions struct {
+	Separators string
+	Quote      rune
+	Escape     rune
+}
+
+// DefaultSplitOptions splits "a, b; \"c, d\"\ne" into a, b, "c, d" and e.
+var DefaultSplitOptions = SplitOptions{Separators: ",;\n", Quote: '"', Escape: '\\'}
+
+func SplitFields(value string) ([]string, error) {
+	return DefaultSplitOptions.Split(value)
+}
+
+// Split trims unquoted whitespace around fields and drops blank fields, like
+// SplitWithoutBlank.
+func (o SplitOptions) Split(value string) ([]string, error) {
+	result := make([]string, 0)
+
+	var field []rune
+	// keep is the length of field without trailing unquoted whitespace.
+	keep := 0
+	quoted, escaped := false, false
+
+	endField := func() {
+		if f := string(field[:keep]); NotBlank(f) {
+			result = append(result, f)
+		}
+		field, keep = field[:0], 0
+	}
+
+	runes := []rune(value)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case escaped:
+			escaped = false
+			field = append(field, r)
+			keep = len(field)
+		case o.Escape != 0 && r == o.Escape:
+			escaped = true
+		case quoted && r == o.Quote:
+			if i+1 < len(runes) && runes[i+1] == o.Quote {
+				i++
+				field = append(field, r)
+				keep = len(field)
+			} else {
+				quoted = false
+			}
+		case quoted:
+			field = append(field, r)
+			keep = len(field)
+		case o.Quote != 0 && r == o.Quote:
+			quoted = true
+			// Quoted whitespace is kept, unquoted whitespace before it is not.
+			field = field[:keep]
+		case strings.ContainsRune(o.Separators, r):
+			endField()
+		case unicode.IsSpace(r):
+			if keep > 0 {
+				field = append(field, r)
+			}
+		default:
+			field = append(field, r)
+			keep = len(field)
+		}
+	}
+
+	if quoted {
+		return nil, ErrUnterminatedQuote
+	}
+	if escaped {
+		field = append(field, o.Escape)
+		keep = len(field)
+	}
+	endField()
+	return result, nil
+}
+
+// DeduplicateFold removes values that differ from an earlier one only in case,
+// keeping the first spelling: "Go", "GO", "go" gives "Go".
+func DeduplicateFold(values []string) []string {
+	caser := cases.Fold()
+	return DeduplicateBy(values, func(v string) string {
+		return caser.String(norm.NFC.String(v))
+	})
+}
+
+// DeduplicateLocale compares values lower cased by the rules of tag, so the
+// Turkish "İstanbul" and "istanbul" are the same but "Istanbul" is not.
+func DeduplicateLocale(values []string, tag language.Tag) []string {
+	caser := cases.Lower(tag)
+	return DeduplicateBy(values, func(v string) string {
+		return caser.String(norm.NFC.String(v))
+	})
+}
+
+// DeduplicateScDf also ignores diacritics and surrounding whitespace, so
+// "Košice", "kosice " and "KOŠICE" keep only "Košice".
+func DeduplicateScDf(values []string) []string {
+	return DeduplicateBy(values, ToScDf)
+}
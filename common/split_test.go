@@ -0,0 +1,62 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestSplitFields(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected []string
+	}{
+		{"", []string{}},
+		{" , ;\n ", []string{}},
+		{"a, b;c\nd", []string{"a", "b", "c", "d"}},
+		{` go , "rust, c" ; `, []string{"go", "rust, c"}},
+		{`"Doe, John" <john@example.com>, jane@example.com`, []string{"Doe, John <john@example.com>", "jane@example.com"}},
+		{`"  padded  "`, []string{"  padded  "}},
+		{`"say ""hi"""`, []string{`say "hi"`}},
+		{`a\,b, c\\d`, []string{"a,b", `c\d`}},
+		{`new york, san  francisco`, []string{"new york", "san  francisco"}},
+		{`trailing\`, []string{`trailing\`}},
+	}
+
+	for _, test := range tests {
+		result, err := SplitFields(test.value)
+		require.NoError(t, err, test.value)
+		assert.Equal(t, test.expected, result, test.value)
+	}
+
+	_, err := SplitFields(`a, "b`)
+	assert.ErrorIs(t, err, ErrUnterminatedQuote)
+}
+
+func TestSplitOptions_Split(t *testing.T) {
+	result, err := SplitOptions{Separators: "|"}.Split(`a|"b"|c\d, e`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", `"b"`, `c\d, e`}, result)
+
+	result, err = SplitOptions{Separators: " ", Quote: '\''}.Split(`tag 'two words'  x`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tag", "two words", "x"}, result)
+}
+
+func TestDeduplicateFold(t *testing.T) {
+	assert.Equal(t, []string{"Go", "Rust"}, DeduplicateFold([]string{"Go", "GO", "Rust", "go", "rust"}))
+	assert.Equal(t, []string{"Straße"}, DeduplicateFold([]string{"Straße", "STRASSE"}))
+	assert.Equal(t, []string{}, DeduplicateFold(nil))
+}
+
+func TestDeduplicateLocale(t *testing.T) {
+	values := []string{"İstanbul", "istanbul", "Istanbul", "ıstanbul"}
+	assert.Equal(t, []string{"İstanbul", "Istanbul"}, DeduplicateLocale(values, language.Turkish))
+	assert.Equal(t, []string{"İstanbul", "istanbul", "ıstanbul"}, DeduplicateLocale(values, language.English))
+}
+
+func TestDeduplicateScDf(t *testing.T) {
+	assert.Equal(t, []string{"Košice", "Žilina"}, DeduplicateScDf([]string{"Košice", "kosice ", "Žilina", "KOŠICE", "zilina"}))
+}
@@ -0,0 +1,138 @@
+package common
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// TextNormalizer prepares text for matching. The steps run in field order.
+type TextNormalizer struct {
+	// Transliterate maps Cyrillic and Greek letters to Latin, "Щука" → "Shchuka".
+	Transliterate bool
+	// RemoveDiacritics drops combining marks and replaces letters that do not
+	// decompose, "Łódź" → "Lodz", "Straße" → "Strasse".
+	RemoveDiacritics bool
+	FoldCase         bool
+	// StripPunctuation replaces punctuation with spaces, "Jean-Luc" → "Jean Luc".
+	StripPunctuation   bool
+	CollapseWhitespace bool
+}
+
+// SearchNormalizer enables every step, for "search by name" columns.
+var SearchNormalizer = TextNormalizer{
+	Transliterate:      true,
+	RemoveDiacritics:   true,
+	FoldCase:           true,
+	StripPunctuation:   true,
+	CollapseWhitespace: true,
+}
+
+func NormalizeForSearch(text string) string {
+	return SearchNormalizer.Normalize(text)
+}
+
+func (n TextNormalizer) Normalize(text string) string {
+	text = norm.NFC.String(text)
+	if n.Transliterate {
+		text = transliterate(text)
+	}
+	if n.RemoveDiacritics {
+		text = removeDiacritics(text)
+	}
+	if n.FoldCase {
+		text = cases.Fold().String(text)
+	}
+	if n.StripPunctuation {
+		text = strings.Map(func(r rune) rune {
+			if unicode.IsPunct(r) {
+				return ' '
+			}
+			return r
+		}, text)
+	}
+	if n.CollapseWhitespace {
+		text = strings.Join(strings.Fields(text), " ")
+	}
+	return text
+}
+
+func removeDiacritics(text string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	result, _, err := transform.String(t, text)
+	if err != nil {
+		return text
+	}
+
+	var sb strings.Builder
+	for _, r := range result {
+		if replacement, ok := latinLetters[r]; ok {
+			sb.WriteString(replacement)
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// latinLetters have no canonical decomposition, so mark removal keeps them.
+var latinLetters = map[rune]string{
+	'ß': "ss", 'ẞ': "SS",
+	'æ': "ae", 'Æ': "AE",
+	'œ': "oe", 'Œ': "OE",
+	'ø': "o", 'Ø': "O",
+	'đ': "d", 'Đ': "D",
+	'ð': "d", 'Ð': "D",
+	'ł': "l", 'Ł': "L",
+	'ħ': "h", 'Ħ': "H",
+	'ŧ': "t", 'Ŧ': "T",
+	'þ': "th", 'Þ': "TH",
+	'ı': "i",
+}
+
+func transliterate(text string) string {
+	var sb strings.Builder
+	for _, r := range text {
+		lower := unicode.ToLower(r)
+		latin, ok := cyrillicLetters[lower]
+		if !ok {
+			latin, ok = greekLetters[lower]
+		}
+		if !ok && unicode.Is(unicode.Greek, lower) {
+			// Accented Greek letters, e.g. ά, are looked up by their base letter.
+			if base := []rune(norm.NFD.String(string(lower))); len(base) > 1 {
+				latin, ok = greekLetters[base[0]]
+			}
+		}
+		if !ok {
+			sb.WriteRune(r)
+			continue
+		}
+		if lower != r && latin != "" {
+			first := []rune(latin)
+			latin = string(unicode.ToUpper(first[0])) + string(first[1:])
+		}
+		sb.WriteString(latin)
+	}
+	return sb.String()
+}
+
+var cyrillicLetters = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "",
+	'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g", 'ў': "u",
+}
+
+var greekLetters = map[rune]string{
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i",
+	'θ': "th", 'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x",
+	'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y",
+	'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+}
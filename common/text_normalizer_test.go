@@ -0,0 +1,43 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeForSearch(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"", ""},
+		{"  Ľudovít   Štúr ", "ludovit stur"},
+		{"Dvořák, Antonín", "dvorak antonin"},
+		{"Jürgen Groß-Müller", "jurgen gross muller"},
+		{"Wałęsa, Lech", "walesa lech"},
+		{"Лев Толстой", "lev tolstoy"},
+		{"Щукин-Юрьев", "shchukin yurev"},
+		{"Σωκράτης", "sokratis"},
+		{"Ἀθῆναι", "athinai"},
+		{"O'Brien\t&\nSons!", "o brien sons"},
+		{"Émile", "emile"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			assert.Equal(t, test.expected, NormalizeForSearch(test.value))
+		})
+	}
+}
+
+func TestTextNormalizer_Steps(t *testing.T) {
+	text := "  Žltý   Кот, ŁÓDŹ! "
+
+	assert.Equal(t, "  Zlty   Кот, LODZ! ", TextNormalizer{RemoveDiacritics: true}.Normalize(text))
+	assert.Equal(t, "  Žltý   Kot, ŁÓDŹ! ", TextNormalizer{Transliterate: true}.Normalize(text))
+	assert.Equal(t, "  žltý   кот, łódź! ", TextNormalizer{FoldCase: true}.Normalize(text))
+	assert.Equal(t, "  Žltý   Кот  ŁÓDŹ  ", TextNormalizer{StripPunctuation: true}.Normalize(text))
+	assert.Equal(t, "Žltý Кот, ŁÓDŹ!", TextNormalizer{CollapseWhitespace: true}.Normalize(text))
+	assert.Equal(t, "ZhUK Zhuk", TextNormalizer{Transliterate: true}.Normalize("ЖУК Жук"))
+}
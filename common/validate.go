@@ -0,0 +1,373 @@
+package common
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"math/big"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+var decimalType = reflect.TypeFor[Decimal]()
+
+var validationRegExps sync.Map
+
+// Validate checks the `validate` tags of a struct (or a pointer, slice or map
+// of structs) and reports every violation in one 400 ServiceError with
+// FieldErrors. A malformed tag is returned as a plain error.
+//
+// Rules are comma separated, e.g. `validate:"required,email,max=255"`:
+//
+//	required            not nil, not empty and not the zero value
+//	notblank / blank    string (not) blank
+//	email               string passes IsValidEmail
+//	min=n / max=n       length of strings, slices and maps, value of numbers and Decimals
+//	len=n               exact length
+//	oneof=a b c         value is one of the space separated options
+//	scale=n             Decimal has at most n fraction digits
+//	precision=n         Decimal has at most n significant digits
+//	regex=pattern       string matches pattern; must be the last rule
+//
+// Apart from required and notblank, rules skip nil pointers and empty strings.
+// Nested structs, slices and maps are validated recursively; `validate:"-"`
+// skips a field. Field paths use json names, e.g. "items[0].name".
+func Validate(v any) error {
+	var fieldErrors []FieldError
+	if err := validateNested(reflect.ValueOf(v), "", &fieldErrors); err != nil {
+		return err
+	}
+	if len(fieldErrors) == 0 {
+		return nil
+	}
+	return Validation("VALIDATION_FAILED", "validation failed", fieldErrors...)
+}
+
+func validateNested(v reflect.Value, path string, fieldErrors *[]FieldError) error {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return validateStruct(v, path, fieldErrors)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := validateNested(v.Index(i), fmt.Sprintf("%s[%d]", path, i), fieldErrors); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		keys := make(map[string]reflect.Value, v.Len())
+		for _, key := range v.MapKeys() {
+			keys[key.String()] = key
+		}
+		for _, key := range slices.Sorted(maps.Keys(keys)) {
+			if err := validateNested(v.MapIndex(keys[key]), fmt.Sprintf("%s[%s]", path, key), fieldErrors); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validateStruct(v reflect.Value, path string, fieldErrors *[]FieldError) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("validate")
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+
+		fieldPath := validationFieldName(field)
+		if path != "" {
+			fieldPath = path + "." + fieldPath
+		}
+
+		rules, err := parseValidationRules(tag)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+		}
+		fv := v.Field(i)
+		for _, rule := range rules {
+			code, message, err := rule.check(fv)
+			if err != nil {
+				return fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+			}
+			if code != "" {
+				*fieldErrors = append(*fieldErrors, FieldError{Field: fieldPath, Code: code, Message: message})
+			}
+		}
+
+		if err := validateNested(fv, fieldPath, fieldErrors); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validationFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+type validationRule struct {
+	name   string
+	param  string
+	regexp *regexp.Regexp
+}
+
+func parseValidationRules(tag string) ([]validationRule, error) {
+	var rules []validationRule
+	for tag != "" {
+		var part string
+		if strings.HasPrefix(tag, "regex=") {
+			part, tag = tag, ""
+		} else {
+			part, tag, _ = strings.Cut(tag, ",")
+		}
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, param, hasParam := strings.Cut(part, "=")
+		rule := validationRule{name: name, param: param}
+		switch name {
+		case "required", "notblank", "blank", "email":
+			if hasParam {
+				return nil, fmt.Errorf("validation rule %s takes no parameter", name)
+			}
+		case "min", "max", "oneof":
+			if param == "" {
+				return nil, fmt.Errorf("validation rule %s requires a parameter", name)
+			}
+		case "len", "scale", "precision":
+			if n, err := strconv.Atoi(param); err != nil || n < 0 {
+				return nil, fmt.Errorf("validation rule %s requires a non-negative integer", name)
+			}
+		case "regex":
+			re, err := validationRegExp(param)
+			if err != nil {
+				return nil, err
+			}
+			rule.regexp = re
+		default:
+			return nil, fmt.Errorf("unknown validation rule %q", name)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func validationRegExp(pattern string) (*regexp.Regexp, error) {
+	if re, ok := validationRegExps.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid validation pattern %q: %w", pattern, err)
+	}
+	validationRegExps.Store(pattern, re)
+	return re, nil
+}
+
+// check returns the violation code and message, or an empty code when the
+// value is valid. The error reports a rule that does not fit the field type.
+func (r validationRule) check(v reflect.Value) (string, string, error) {
+	if r.name == "required" {
+		if isValidationEmpty(v) || v.IsZero() || hasLen(v) && v.Len() == 0 {
+			return "REQUIRED", "is required", nil
+		}
+		return "", "", nil
+	}
+
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			if r.name == "notblank" {
+				return "NOT_BLANK", "must not be blank", nil
+			}
+			return "", "", nil
+		}
+		v = v.Elem()
+	}
+
+	switch r.name {
+	case "notblank":
+		if v.Kind() != reflect.String {
+			return "", "", fmt.Errorf("rule notblank needs a string, got %s", v.Type())
+		}
+		if IsBlank(v.String()) {
+			return "NOT_BLANK", "must not be blank", nil
+		}
+		return "", "", nil
+	case "blank":
+		if v.Kind() != reflect.String {
+			return "", "", fmt.Errorf("rule blank needs a string, got %s", v.Type())
+		}
+		if NotBlank(v.String()) {
+			return "BLANK", "must be blank", nil
+		}
+		return "", "", nil
+	}
+
+	if v.Kind() == reflect.String && v.Len() == 0 {
+		return "", "", nil
+	}
+
+	switch r.name {
+	case "email":
+		if v.Kind() != reflect.String {
+			return "", "", fmt.Errorf("rule email needs a string, got %s", v.Type())
+		}
+		if !IsValidEmail(v.String()) {
+			return "EMAIL", "must be a valid email address", nil
+		}
+	case "regex":
+		if v.Kind() != reflect.String {
+			return "", "", fmt.Errorf("rule regex needs a string, got %s", v.Type())
+		}
+		if !r.regexp.MatchString(v.String()) {
+			return "PATTERN", "must match " + r.param, nil
+		}
+	case "oneof":
+		options := strings.Fields(r.param)
+		if !slices.Contains(options, fmt.Sprint(v.Interface())) {
+			return "ONE_OF", "must be one of " + strings.Join(options, ", "), nil
+		}
+	case "len":
+		if !hasLen(v) {
+			return "", "", fmt.Errorf("rule len needs a string, slice or map, got %s", v.Type())
+		}
+		n, _ := strconv.Atoi(r.param)
+		if validationLen(v) != n {
+			return "LEN", fmt.Sprintf("must have length %d", n), nil
+		}
+	case "min", "max":
+		return r.checkBound(v)
+	case "scale", "precision":
+		if v.Type() != decimalType {
+			return "", "", fmt.Errorf("rule %s needs a Decimal, got %s", r.name, v.Type())
+		}
+		n, _ := strconv.Atoi(r.param)
+		scale, precision := decimalDigits(v.Interface().(Decimal))
+		if r.name == "scale" && scale > n {
+			return "SCALE", fmt.Sprintf("must have at most %d fraction digits", n), nil
+		}
+		if r.name == "precision" && precision > n {
+			return "PRECISION", fmt.Sprintf("must have at most %d digits", n), nil
+		}
+	}
+	return "", "", nil
+}
+
+func (r validationRule) checkBound(v reflect.Value) (string, string, error) {
+	code := strings.ToUpper(r.name)
+	result := 0
+	message := ""
+	switch {
+	case hasLen(v):
+		n, err := strconv.Atoi(r.param)
+		if err != nil {
+			return "", "", fmt.Errorf("rule %s requires an integer length", r.name)
+		}
+		result = cmp.Compare(validationLen(v), n)
+		if r.name == "min" {
+			message = fmt.Sprintf("must have at least %d characters or items", n)
+		} else {
+			message = fmt.Sprintf("must have at most %d characters or items", n)
+		}
+	default:
+		value, err := validationNumber(v)
+		if err != nil {
+			return "", "", err
+		}
+		bound, ok := new(big.Rat).SetString(r.param)
+		if !ok {
+			return "", "", fmt.Errorf("rule %s requires a number", r.name)
+		}
+		result = value.Cmp(bound)
+		if r.name == "min" {
+			message = "must be at least " + r.param
+		} else {
+			message = "must be at most " + r.param
+		}
+	}
+
+	if r.name == "min" && result < 0 || r.name == "max" && result > 0 {
+		return code, message, nil
+	}
+	return "", "", nil
+}
+
+func validationNumber(v reflect.Value) (*big.Rat, error) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return new(big.Rat).SetInt64(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Rat).SetInt(new(big.Int).SetUint64(v.Uint())), nil
+	case reflect.Float32, reflect.Float64:
+		r, ok := new(big.Rat).SetString(strconv.FormatFloat(v.Float(), 'g', -1, 64))
+		if !ok {
+			return nil, fmt.Errorf("cannot compare %v", v.Float())
+		}
+		return r, nil
+	}
+	if v.Type() == decimalType {
+		return v.Interface().(Decimal).Rat(), nil
+	}
+	return nil, fmt.Errorf("rules min and max need a number, string, slice or map, got %s", v.Type())
+}
+
+func hasLen(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	}
+	return false
+}
+
+// validationLen counts characters, not bytes, for strings.
+func validationLen(v reflect.Value) int {
+	if v.Kind() == reflect.String {
+		return utf8.RuneCountInString(v.String())
+	}
+	return v.Len()
+}
+
+func isValidationEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+		return v.IsNil()
+	}
+	return false
+}
+
+// decimalDigits ignores trailing fraction zeros, so 1.50 has scale 1 and
+// precision 2.
+func decimalDigits(d Decimal) (int, int) {
+	intPart, fracPart, _ := strings.Cut(d.Abs().String(), ".")
+	fracPart = strings.TrimRight(fracPart, "0")
+	intPart = strings.TrimLeft(intPart, "0")
+
+	precision := len(intPart) + len(fracPart)
+	if intPart == "" {
+		precision = len(strings.TrimLeft(fracPart, "0"))
+	}
+	return len(fracPart), max(precision, 1)
+}
@@ -0,0 +1,141 @@
+package common
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type validateTestItem struct {
+	Name     string  `json:"name" validate:"notblank,max=5"`
+	Quantity int     `json:"quantity" validate:"min=1,max=10"`
+	Price    Decimal `json:"price" validate:"min=0,scale=2,precision=6"`
+}
+
+type validateTestOrder struct {
+	Email    string             `json:"email" validate:"required,email,max=255"`
+	Status   string             `json:"status" validate:"oneof=NEW PAID"`
+	Code     string             `json:"code,omitempty" validate:"len=4,regex=^[A-Z]{2}[0-9]{1,2}$"`
+	Note     *string            `json:"note" validate:"notblank"`
+	Internal string             `json:"-" validate:"blank"`
+	Items    []validateTestItem `json:"items" validate:"required,max=3"`
+	Customer *struct {
+		Name string `json:"name" validate:"required"`
+	} `json:"customer"`
+	Tags    map[string]validateTestItem `json:"tags"`
+	Ignored string                      `validate:"-"`
+}
+
+func validFieldErrors(t *testing.T, err error) []FieldError {
+	var se *ServiceError
+	require.ErrorAs(t, err, &se)
+	assert.Equal(t, http.StatusBadRequest, se.Status)
+	assert.Equal(t, "VALIDATION_FAILED", se.Code)
+	return se.FieldErrors
+}
+
+func TestValidate_Valid(t *testing.T) {
+	note := "deliver fast"
+	order := validateTestOrder{
+		Email:  "john@example.com",
+		Status: "PAID",
+		Code:   "AB12",
+		Note:   &note,
+		Items:  []validateTestItem{{Name: "tea", Quantity: 2, Price: MustParseDecimal("1234.50")}},
+	}
+	assert.NoError(t, Validate(&order))
+	assert.NoError(t, Validate([]validateTestOrder{order}))
+}
+
+func TestValidate_Violations(t *testing.T) {
+	blank := "  "
+	order := validateTestOrder{
+		Email:    "not-an-email",
+		Status:   "LOST",
+		Code:     "ab12",
+		Note:     &blank,
+		Internal: "x",
+		Items: []validateTestItem{
+			{Name: "coffee", Quantity: 0, Price: MustParseDecimal("0.001")},
+			{Name: "tea", Quantity: 11, Price: MustParseDecimal("-123456.70")},
+		},
+		Customer: &struct {
+			Name string `json:"name" validate:"required"`
+		}{},
+		Tags:    map[string]validateTestItem{"gift": {Name: " ", Quantity: 1}},
+		Ignored: "anything",
+	}
+
+	assert.Equal(t, []FieldError{
+		{Field: "email", Code: "EMAIL", Message: "must be a valid email address"},
+		{Field: "status", Code: "ONE_OF", Message: "must be one of NEW, PAID"},
+		{Field: "code", Code: "PATTERN", Message: "must match ^[A-Z]{2}[0-9]{1,2}$"},
+		{Field: "note", Code: "NOT_BLANK", Message: "must not be blank"},
+		{Field: "Internal", Code: "BLANK", Message: "must be blank"},
+		{Field: "items[0].name", Code: "MAX", Message: "must have at most 5 characters or items"},
+		{Field: "items[0].quantity", Code: "MIN", Message: "must be at least 1"},
+		{Field: "items[0].price", Code: "SCALE", Message: "must have at most 2 fraction digits"},
+		{Field: "items[1].quantity", Code: "MAX", Message: "must be at most 10"},
+		{Field: "items[1].price", Code: "MIN", Message: "must be at least 0"},
+		{Field: "items[1].price", Code: "PRECISION", Message: "must have at most 6 digits"},
+		{Field: "customer.name", Code: "REQUIRED", Message: "is required"},
+		{Field: "tags[gift].name", Code: "NOT_BLANK", Message: "must not be blank"},
+	}, validFieldErrors(t, Validate(order)))
+}
+
+func TestValidate_Required(t *testing.T) {
+	assert.Equal(t, []FieldError{
+		{Field: "email", Code: "REQUIRED", Message: "is required"},
+		{Field: "note", Code: "NOT_BLANK", Message: "must not be blank"},
+		{Field: "items", Code: "REQUIRED", Message: "is required"},
+	}, validFieldErrors(t, Validate(validateTestOrder{Items: []validateTestItem{}})))
+}
+
+func TestValidate_EmailWithLineBreak(t *testing.T) {
+	err := Validate(struct {
+		Email string `json:"email" validate:"email"`
+	}{Email: "a@b.com\r\n"})
+	assert.True(t, IsCode(err, "VALIDATION_FAILED"))
+}
+
+func TestValidate_InvalidTags(t *testing.T) {
+	assert.Error(t, Validate(struct {
+		A string `validate:"unknown"`
+	}{}))
+	assert.Error(t, Validate(struct {
+		A string `validate:"len=x"`
+	}{}))
+	assert.Error(t, Validate(struct {
+		A int `validate:"email"`
+	}{A: 1}))
+	assert.Error(t, Validate(struct {
+		A string `validate:"regex=("`
+	}{A: "x"}))
+
+	err := Validate(struct {
+		A string `validate:"required"`
+	}{})
+	assert.True(t, IsCode(err, "VALIDATION_FAILED"))
+}
+
+func TestDecimalDigits(t *testing.T) {
+	tests := []struct {
+		value     string
+		scale     int
+		precision int
+	}{
+		{"0", 0, 1},
+		{"1.50", 1, 2},
+		{"-123.456", 3, 6},
+		{"0.0012", 4, 2},
+		{"1000", 0, 4},
+	}
+
+	for _, tc := range tests {
+		scale, precision := decimalDigits(MustParseDecimal(tc.value))
+		assert.Equal(t, tc.scale, scale, tc.value)
+		assert.Equal(t, tc.precision, precision, tc.value)
+	}
+}
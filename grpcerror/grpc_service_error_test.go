@@ -0,0 +1,134 @@
+package grpcerror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/janobono/go-util/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		status int
+		code   codes.Code
+	}{
+		{http.StatusBadRequest, codes.InvalidArgument},
+		{http.StatusUnauthorized, codes.Unauthenticated},
+		{http.StatusForbidden, codes.PermissionDenied},
+		{http.StatusNotFound, codes.NotFound},
+		{http.StatusConflict, codes.AlreadyExists},
+		{http.StatusTooManyRequests, codes.ResourceExhausted},
+		{http.StatusServiceUnavailable, codes.Unavailable},
+		{http.StatusTeapot, codes.FailedPrecondition},
+		{http.StatusBadGateway, codes.Internal},
+		{0, codes.Unknown},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.code, Code(tc.status), tc.status)
+	}
+
+	for _, status := range []int{400, 401, 403, 404, 409, 429, 499, 501, 503, 504} {
+		assert.Equal(t, status, HttpStatus(Code(status)), status)
+	}
+	assert.Equal(t, http.StatusInternalServerError, HttpStatus(codes.DataLoss))
+}
+
+func TestError_RoundTrip(t *testing.T) {
+	se := common.NewServiceError(http.StatusUnprocessableEntity, "INVALID_USER", "user is invalid")
+	se.FieldErrors = []common.FieldError{{Field: "email", Code: "EMAIL", Message: "must be an email"}}
+
+	err := ToError(fmt.Errorf("create: %w", se))
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Equal(t, "user is invalid", st.Message())
+
+	var rebuilt *common.ServiceError
+	require.ErrorAs(t, FromError(err), &rebuilt)
+	assert.Equal(t, se, rebuilt)
+
+	plain := errors.New("plain")
+	assert.Same(t, plain, ToError(plain))
+	foreign := status.Error(codes.NotFound, "missing")
+	assert.Equal(t, foreign, FromError(foreign))
+	assert.NoError(t, FromError(nil))
+}
+
+func TestInterceptors(t *testing.T) {
+	se := common.NewServiceError(http.StatusNotFound, "USER_NOT_FOUND", "user not found")
+
+	unary := UnaryServerInterceptor()
+	_, err := unary(context.Background(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+		return nil, fmt.Errorf("get: %w", se)
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	resp, err := unary(context.Background(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+		return "ok", nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+
+	stream := StreamServerInterceptor()
+	err = stream(nil, nil, &grpc.StreamServerInfo{}, func(srv any, ss grpc.ServerStream) error {
+		return se
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	client := UnaryClientInterceptor()
+	err = client(context.Background(), "/svc/Get", nil, nil, nil,
+		func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return Status(se).Err()
+		})
+	assert.True(t, common.IsCode(err, "USER_NOT_FOUND"))
+}
+
+type testClientStream struct {
+	grpc.ClientStream
+	err error
+}
+
+func (s *testClientStream) Header() (metadata.MD, error) { return nil, s.err }
+func (s *testClientStream) SendMsg(any) error            { return s.err }
+func (s *testClientStream) RecvMsg(any) error            { return s.err }
+func (s *testClientStream) CloseSend() error             { return s.err }
+
+func TestStreamClientInterceptor(t *testing.T) {
+	se := common.NewServiceError(http.StatusConflict, "USER_EXISTS", "user exists")
+	interceptor := StreamClientInterceptor()
+
+	_, err := interceptor(context.Background(), &grpc.StreamDesc{}, nil, "/svc/Watch",
+		func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return nil, Status(se).Err()
+		})
+	assert.True(t, common.IsCode(err, "USER_EXISTS"))
+
+	stream, err := interceptor(context.Background(), &grpc.StreamDesc{}, nil, "/svc/Watch",
+		func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return &testClientStream{err: Status(se).Err()}, nil
+		})
+	require.NoError(t, err)
+	_, err = stream.Header()
+	assert.True(t, common.IsCode(err, "USER_EXISTS"))
+	assert.True(t, common.IsCode(stream.SendMsg(nil), "USER_EXISTS"))
+	assert.True(t, common.IsCode(stream.RecvMsg(nil), "USER_EXISTS"))
+	assert.True(t, common.IsCode(stream.CloseSend(), "USER_EXISTS"))
+
+	stream, err = interceptor(context.Background(), &grpc.StreamDesc{}, nil, "/svc/Watch",
+		func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return &testClientStream{err: io.EOF}, nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, io.EOF, stream.RecvMsg(nil))
+}